@@ -0,0 +1,256 @@
+package checker
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/0xReLogic/SENTINEL/config"
+)
+
+// BodyAssertion checks a response body and returns an error describing why it failed
+type BodyAssertion func(body []byte) error
+
+// CompileAssertions turns the configured assertions into BodyAssertions,
+// rejecting assertions that are empty, ambiguous or malformed.
+func CompileAssertions(assertions []config.Assertion) ([]BodyAssertion, error) {
+	compiled := make([]BodyAssertion, 0, len(assertions))
+
+	for i, a := range assertions {
+		set := 0
+		for _, field := range []string{a.Contains, a.NotContains, a.Regex, a.JSON} {
+			if field != "" {
+				set++
+			}
+		}
+		if set != 1 {
+			return nil, fmt.Errorf("assertion #%d: exactly one of contains, not_contains, regex or json must be set", i+1)
+		}
+
+		switch {
+		case a.Contains != "":
+			compiled = append(compiled, containsAssertion(a.Contains))
+		case a.NotContains != "":
+			compiled = append(compiled, notContainsAssertion(a.NotContains))
+		case a.Regex != "":
+			re, err := regexp.Compile(a.Regex)
+			if err != nil {
+				return nil, fmt.Errorf("assertion #%d: invalid regex: %w", i+1, err)
+			}
+			compiled = append(compiled, regexAssertion(re))
+		case a.JSON != "":
+			assertion, err := parseJSONAssertion(a.JSON)
+			if err != nil {
+				return nil, fmt.Errorf("assertion #%d: %w", i+1, err)
+			}
+			compiled = append(compiled, assertion)
+		}
+	}
+
+	return compiled, nil
+}
+
+func containsAssertion(text string) BodyAssertion {
+	return func(body []byte) error {
+		if !bytes.Contains(body, []byte(text)) {
+			return fmt.Errorf("body does not contain %q", text)
+		}
+		return nil
+	}
+}
+
+func notContainsAssertion(text string) BodyAssertion {
+	return func(body []byte) error {
+		if bytes.Contains(body, []byte(text)) {
+			return fmt.Errorf("body contains %q", text)
+		}
+		return nil
+	}
+}
+
+func regexAssertion(re *regexp.Regexp) BodyAssertion {
+	return func(body []byte) error {
+		if !re.Match(body) {
+			return fmt.Errorf("body does not match regex %q", re.String())
+		}
+		return nil
+	}
+}
+
+// jsonOperators lists the supported comparison operators, two-character
+// operators first so that "<=" is not read as "<".
+var jsonOperators = []string{"==", "!=", "<=", ">=", "<", ">"}
+
+// parseJSONAssertion parses an expression of the form `$.path op literal`,
+// e.g. `$.status == "ok"` or `$.queue_depth < 100`. A bare path only asserts
+// that the value exists.
+func parseJSONAssertion(expr string) (BodyAssertion, error) {
+	expr = strings.TrimSpace(expr)
+
+	end := strings.IndexAny(expr, " \t=!<>")
+	if end == -1 {
+		end = len(expr)
+	}
+	pathExpr := expr[:end]
+	rest := strings.TrimSpace(expr[end:])
+
+	path, err := parseJSONPath(pathExpr)
+	if err != nil {
+		return nil, err
+	}
+
+	if rest == "" {
+		return func(body []byte) error {
+			doc, err := decodeJSON(body)
+			if err != nil {
+				return err
+			}
+			if _, err := lookupJSONPath(doc, path); err != nil {
+				return fmt.Errorf("json path %s: %w", pathExpr, err)
+			}
+			return nil
+		}, nil
+	}
+
+	var op string
+	for _, candidate := range jsonOperators {
+		if strings.HasPrefix(rest, candidate) {
+			op = candidate
+			break
+		}
+	}
+	if op == "" {
+		return nil, fmt.Errorf("invalid json assertion %q: expected one of %s", expr, strings.Join(jsonOperators, " "))
+	}
+
+	literal := strings.TrimSpace(rest[len(op):])
+	var expected interface{}
+	if err := json.Unmarshal([]byte(literal), &expected); err != nil {
+		return nil, fmt.Errorf("invalid json assertion %q: value %s is not a JSON literal", expr, literal)
+	}
+	if _, isNumber := expected.(float64); !isNumber && op != "==" && op != "!=" {
+		return nil, fmt.Errorf("invalid json assertion %q: %s requires a number", expr, op)
+	}
+
+	return func(body []byte) error {
+		doc, err := decodeJSON(body)
+		if err != nil {
+			return err
+		}
+		actual, err := lookupJSONPath(doc, path)
+		if err != nil {
+			return fmt.Errorf("json path %s: %w", pathExpr, err)
+		}
+		if !compareJSON(actual, op, expected) {
+			got, _ := json.Marshal(actual)
+			return fmt.Errorf("json path %s is %s, expected %s %s", pathExpr, got, op, literal)
+		}
+		return nil
+	}, nil
+}
+
+func decodeJSON(body []byte) (interface{}, error) {
+	var doc interface{}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return nil, fmt.Errorf("body is not valid JSON: %w", err)
+	}
+	return doc, nil
+}
+
+// parseJSONPath splits a path such as $.items[0].name into its segments.
+// Object keys are returned as strings and array indexes as ints.
+func parseJSONPath(expr string) ([]interface{}, error) {
+	if !strings.HasPrefix(expr, "$") {
+		return nil, fmt.Errorf("invalid json path %q: must start with $", expr)
+	}
+
+	var segments []interface{}
+	rest := expr[1:]
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end == -1 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("invalid json path %q: empty key", expr)
+			}
+			segments = append(segments, rest[:end])
+			rest = rest[end:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end == -1 {
+				return nil, fmt.Errorf("invalid json path %q: unclosed [", expr)
+			}
+			index, err := strconv.Atoi(rest[1:end])
+			if err != nil || index < 0 {
+				return nil, fmt.Errorf("invalid json path %q: bad index %q", expr, rest[1:end])
+			}
+			segments = append(segments, index)
+			rest = rest[end+1:]
+		default:
+			return nil, fmt.Errorf("invalid json path %q: unexpected %q", expr, rest[0])
+		}
+	}
+
+	return segments, nil
+}
+
+var errPathNotFound = errors.New("not found")
+
+func lookupJSONPath(doc interface{}, path []interface{}) (interface{}, error) {
+	current := doc
+	for _, segment := range path {
+		switch key := segment.(type) {
+		case string:
+			object, ok := current.(map[string]interface{})
+			if !ok {
+				return nil, errPathNotFound
+			}
+			if current, ok = object[key]; !ok {
+				return nil, errPathNotFound
+			}
+		case int:
+			array, ok := current.([]interface{})
+			if !ok || key >= len(array) {
+				return nil, errPathNotFound
+			}
+			current = array[key]
+		}
+	}
+	return current, nil
+}
+
+func compareJSON(actual interface{}, op string, expected interface{}) bool {
+	switch op {
+	case "==":
+		return reflect.DeepEqual(actual, expected)
+	case "!=":
+		return !reflect.DeepEqual(actual, expected)
+	}
+
+	a, ok := actual.(float64)
+	if !ok {
+		return false
+	}
+	e := expected.(float64)
+
+	switch op {
+	case "<":
+		return a < e
+	case "<=":
+		return a <= e
+	case ">":
+		return a > e
+	case ">=":
+		return a >= e
+	}
+	return false
+}
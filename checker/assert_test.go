@@ -0,0 +1,114 @@
+package checker
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0xReLogic/SENTINEL/config"
+)
+
+const testJSONBody = `{"status":"ok","queue_depth":42,"ready":true,"items":[{"name":"primary"}]}`
+
+func TestCompileAssertions(t *testing.T) {
+	tests := []struct {
+		name       string
+		assertion  config.Assertion
+		wantErr    bool
+		wantFailed bool
+	}{
+		{name: "contains", assertion: config.Assertion{Contains: `"status":"ok"`}},
+		{name: "contains fails", assertion: config.Assertion{Contains: "maintenance"}, wantFailed: true},
+		{name: "not contains", assertion: config.Assertion{NotContains: "error"}},
+		{name: "not contains fails", assertion: config.Assertion{NotContains: "queue_depth"}, wantFailed: true},
+		{name: "regex", assertion: config.Assertion{Regex: `"queue_depth":\d+`}},
+		{name: "regex fails", assertion: config.Assertion{Regex: `^<html>`}, wantFailed: true},
+		{name: "json equality", assertion: config.Assertion{JSON: `$.status == "ok"`}},
+		{name: "json equality fails", assertion: config.Assertion{JSON: `$.status == "degraded"`}, wantFailed: true},
+		{name: "json inequality", assertion: config.Assertion{JSON: `$.status != "down"`}},
+		{name: "json less than", assertion: config.Assertion{JSON: `$.queue_depth < 100`}},
+		{name: "json less than fails", assertion: config.Assertion{JSON: `$.queue_depth<10`}, wantFailed: true},
+		{name: "json greater or equal", assertion: config.Assertion{JSON: `$.queue_depth >= 42`}},
+		{name: "json boolean", assertion: config.Assertion{JSON: `$.ready == true`}},
+		{name: "json array index", assertion: config.Assertion{JSON: `$.items[0].name == "primary"`}},
+		{name: "json exists", assertion: config.Assertion{JSON: `$.items[0]`}},
+		{name: "json missing path", assertion: config.Assertion{JSON: `$.items[3].name == "x"`}, wantFailed: true},
+		{name: "empty assertion", assertion: config.Assertion{}, wantErr: true},
+		{name: "ambiguous assertion", assertion: config.Assertion{Contains: "a", Regex: "b"}, wantErr: true},
+		{name: "invalid regex", assertion: config.Assertion{Regex: "("}, wantErr: true},
+		{name: "json path without $", assertion: config.Assertion{JSON: `status == "ok"`}, wantErr: true},
+		{name: "json unknown operator", assertion: config.Assertion{JSON: `$.status ~ "ok"`}, wantErr: true},
+		{name: "json bad literal", assertion: config.Assertion{JSON: `$.status == ok`}, wantErr: true},
+		{name: "json ordering on string", assertion: config.Assertion{JSON: `$.status < "z"`}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertions, err := CompileAssertions([]config.Assertion{tt.assertion})
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("Expected compile error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CompileAssertions failed: %v", err)
+			}
+
+			err = assertions[0]([]byte(testJSONBody))
+			if tt.wantFailed && err == nil {
+				t.Error("Expected assertion to fail, got nil")
+			}
+			if !tt.wantFailed && err != nil {
+				t.Errorf("Expected assertion to pass, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestCheckAssertions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/maintenance":
+			w.Write([]byte("<html>Down for maintenance</html>"))
+		case "/large":
+			w.Write([]byte(strings.Repeat("x", 2048)))
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(testJSONBody))
+		}
+	}))
+	defer server.Close()
+
+	healthy := []config.Assertion{
+		{NotContains: "maintenance"},
+		{JSON: `$.status == "ok"`},
+	}
+
+	status := Check(config.Service{Name: "API", URL: server.URL + "/health", Timeout: 2 * time.Second, Assertions: healthy})
+	if !status.IsUp {
+		t.Errorf("Expected service to be UP, got error: %v", status.Error)
+	}
+
+	status = Check(config.Service{Name: "API", URL: server.URL + "/maintenance", Timeout: 2 * time.Second, Assertions: healthy})
+	if status.IsUp {
+		t.Error("Expected service with maintenance page to be DOWN")
+	}
+	if status.Error == nil || !strings.Contains(status.Error.Error(), `assertion failed: body contains "maintenance"`) {
+		t.Errorf("Expected assertion failure reason, got: %v", status.Error)
+	}
+	if status.StatusCode != http.StatusOK {
+		t.Errorf("Expected status code 200 to be kept, got %d", status.StatusCode)
+	}
+
+	status = Check(config.Service{Name: "API", URL: server.URL + "/large", Timeout: 2 * time.Second, MaxBodySize: 1024,
+		Assertions: []config.Assertion{{Contains: "x"}}})
+	if status.IsUp {
+		t.Error("Expected oversized body to fail the check")
+	}
+	if status.Error == nil || !strings.Contains(status.Error.Error(), "max_body_size") {
+		t.Errorf("Expected max_body_size error, got: %v", status.Error)
+	}
+}
@@ -4,7 +4,10 @@ package checker
 
 import (
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/0xReLogic/SENTINEL/config"
@@ -12,58 +15,228 @@ import (
 
 // ServiceStatus represents the result of a service check
 type ServiceStatus struct {
+	ID           string
 	Name         string
 	URL          string
 	IsUp         bool
 	ResponseTime time.Duration
 	StatusCode   int
 	Error        error
+	TLS          *CertificateInfo
+
+	// Degraded is set when the service is up but responded slower than its
+	// degraded threshold
+	Degraded bool
+
+	// Attempts holds every try made within this check when retries are
+	// configured, the last one being the reported result
+	Attempts []Attempt
+
+	// State is the confirmed service state once failure and success
+	// thresholds are applied. It is set by the caller tracking service state
+	// and may differ from IsUp while a state change is pending.
+	State string
+}
+
+// Attempt records the outcome of a single try within one check
+type Attempt struct {
+	ResponseTime time.Duration
+	StatusCode   int
+	Error        error
+}
+
+// Service states, both for a single result and the confirmed state
+const (
+	StateUp       = "UP"
+	StateDegraded = "DEGRADED"
+	StateDown     = "DOWN"
+)
+
+// CertificateInfo describes the leaf certificate presented by an HTTPS service
+type CertificateInfo struct {
+	NotAfter time.Time
+	Issuer   string
+	DNSNames []string
+}
+
+// ExpiresWithin reports whether the certificate expires within d of now
+func (c *CertificateInfo) ExpiresWithin(d time.Duration, now time.Time) bool {
+	return c != nil && c.NotAfter.Sub(now) <= d
+}
+
+// Result returns the state reported by this check alone: UP, DEGRADED or DOWN
+func (s ServiceStatus) Result() string {
+	switch {
+	case !s.IsUp:
+		return StateDown
+	case s.Degraded:
+		return StateDegraded
+	default:
+		return StateUp
+	}
 }
 
 // String returns a formatted string representation of the service status
 func (s ServiceStatus) String() string {
-	status := "UP"
-	if !s.IsUp {
-		status = "DOWN"
+	status := s.Result()
+	// Show the confirmed state while a state change is pending
+	if s.State != "" && s.State != status {
+		status = fmt.Sprintf("%s, state %s", status, s.State)
 	}
 
 	if s.Error != nil {
-		return fmt.Sprintf("[%s] %s - Error: %s", status, s.Name, s.Error)
+		return fmt.Sprintf("[%s] %s - Error: %s%s", status, s.Name, s.Error, s.attemptsSuffix())
+	}
+
+	if s.StatusCode == 0 {
+		return fmt.Sprintf("[%s] %s - %d ms%s", status, s.Name, s.ResponseTime.Milliseconds(), s.attemptsSuffix())
+	}
+
+	return fmt.Sprintf("[%s] %s - %d ms (HTTP %d)%s", status, s.Name, s.ResponseTime.Milliseconds(), s.StatusCode, s.attemptsSuffix())
+}
+
+// AttemptSummary describes how many attempts a check needed, e.g.
+// "succeeded on attempt 3". It is empty when the first attempt was final.
+func (s ServiceStatus) AttemptSummary() string {
+	if len(s.Attempts) <= 1 {
+		return ""
 	}
+	if s.IsUp {
+		return fmt.Sprintf("succeeded on attempt %d", len(s.Attempts))
+	}
+	return fmt.Sprintf("failed after %d attempts", len(s.Attempts))
+}
 
-	return fmt.Sprintf("[%s] %s - %d ms (HTTP %d)", status, s.Name, s.ResponseTime.Milliseconds(), s.StatusCode)
+func (s ServiceStatus) attemptsSuffix() string {
+	if summary := s.AttemptSummary(); summary != "" {
+		return " (" + summary + ")"
+	}
+	return ""
 }
 
 // CheckService performs an HTTP GET request to the given URL and returns the service status
 func CheckService(name, url string, timeout time.Duration) ServiceStatus {
+	return Check(config.Service{
+		Name:    name,
+		URL:     url,
+		Timeout: timeout,
+	})
+}
+
+func init() {
+	Register(config.TypeHTTP, HTTPChecker{})
+}
+
+// HTTPChecker is the default checker. It sends the HTTP request described by
+// the service configuration and evaluates the response.
+type HTTPChecker struct{}
+
+// Validate checks the URL, method, expected_status rules and assertions of
+// an HTTP service
+func (HTTPChecker) Validate(service config.Service) []error {
+	var errors []error
+	if service.URL == "" {
+		errors = append(errors, fmt.Errorf("URL is required"))
+	} else if !isValidURL(service.URL) {
+		errors = append(errors, fmt.Errorf("invalid URL format '%s'", service.URL))
+	}
+	if service.Method != "" && !isValidMethod(service.Method) {
+		errors = append(errors, fmt.Errorf("unsupported HTTP method '%s'", service.Method))
+	}
+	if _, err := ParseStatusRules(service.ExpectedStatus); err != nil {
+		errors = append(errors, fmt.Errorf("invalid expected_status: %v", err))
+	}
+	if _, err := CompileAssertions(service.Assertions); err != nil {
+		errors = append(errors, err)
+	}
+	return errors
+}
+
+// isValidURL checks if a string is a valid HTTP/HTTPS URL
+func isValidURL(urlStr string) bool {
+	u, err := url.Parse(urlStr)
+	return err == nil && u.Scheme != "" && u.Host != "" &&
+		(u.Scheme == "http" || u.Scheme == "https")
+}
+
+// isValidMethod checks if a string is a supported HTTP request method
+func isValidMethod(method string) bool {
+	switch strings.ToUpper(method) {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut,
+		http.MethodPatch, http.MethodDelete, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// Check sends the HTTP request for the service and returns the service status
+func (HTTPChecker) Check(service config.Service) ServiceStatus {
 	result := ServiceStatus{
-		Name: name,
-		URL:  url,
+		Name: service.Name,
+		URL:  service.URL,
 	}
 
+	timeout := service.Timeout
 	if timeout <= 0 {
 		timeout = config.DefaultTimeout
 	}
 
+	statusMatcher, err := ParseStatusRules(service.ExpectedStatus)
+	if err != nil {
+		result.IsUp = false
+		result.Error = fmt.Errorf("invalid expected_status: %w", err)
+		return result
+	}
+
+	assertions, err := CompileAssertions(service.Assertions)
+	if err != nil {
+		result.IsUp = false
+		result.Error = fmt.Errorf("invalid assertions: %w", err)
+		return result
+	}
+
+	req, err := newRequest(service)
+	if err != nil {
+		result.IsUp = false
+		result.Error = err
+		return result
+	}
+
 	// Create HTTP client with timeout
 	client := &http.Client{
 		Timeout: timeout,
 	}
 
+	// With explicit status rules the first response is evaluated as-is,
+	// so redirects can be accepted or rejected by the rules
+	if len(service.ExpectedStatus) > 0 {
+		client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+	}
+
 	// Record start time
 	startTime := time.Now()
 
-	// Send HTTP GET request
-	resp, err := client.Get(url)
+	// Send HTTP request
+	resp, err := client.Do(req)
 
 	// Calculate response time
 	result.ResponseTime = time.Since(startTime)
-	
-	// Set status code
+
+	// Set status code and TLS certificate details
 	if resp != nil {
 		result.StatusCode = resp.StatusCode
+		if resp.TLS != nil && len(resp.TLS.PeerCertificates) > 0 {
+			leaf := resp.TLS.PeerCertificates[0]
+			result.TLS = &CertificateInfo{
+				NotAfter: leaf.NotAfter,
+				Issuer:   leaf.Issuer.String(),
+				DNSNames: leaf.DNSNames,
+			}
+		}
 	}
-	
+
 	// Handle errors
 	if err != nil {
 		result.IsUp = false
@@ -71,9 +244,73 @@ func CheckService(name, url string, timeout time.Duration) ServiceStatus {
 		return result
 	}
 	defer resp.Body.Close()
-	
-	// Determine if service is up (2xx or 3xx status codes)
-	result.IsUp = resp.StatusCode >= 200 && resp.StatusCode < 400
+
+	// Determine if service is up (2xx or 3xx status codes unless expected_status is set)
+	result.IsUp = statusMatcher.Match(resp.StatusCode)
+	if !result.IsUp || len(assertions) == 0 {
+		return result
+	}
+
+	// Run body assertions against a size-capped read of the response
+	body, err := readBody(resp.Body, service.MaxBodySize)
+	if err != nil {
+		result.IsUp = false
+		result.Error = err
+		return result
+	}
+	for _, assertion := range assertions {
+		if err := assertion(body); err != nil {
+			result.IsUp = false
+			result.Error = fmt.Errorf("assertion failed: %w", err)
+			return result
+		}
+	}
 
 	return result
 }
+
+// readBody reads at most maxSize bytes of a response body and fails if the
+// body is larger than that
+func readBody(r io.Reader, maxSize int64) ([]byte, error) {
+	if maxSize <= 0 {
+		maxSize = config.DefaultMaxBodySize
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r, maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	if int64(len(body)) > maxSize {
+		return nil, fmt.Errorf("response body exceeds max_body_size of %d bytes", maxSize)
+	}
+	return body, nil
+}
+
+// newRequest builds the HTTP request for a service from its method, headers and body
+func newRequest(service config.Service) (*http.Request, error) {
+	method := strings.ToUpper(service.Method)
+	if method == "" {
+		method = config.DefaultMethod
+	}
+
+	var body io.Reader
+	if service.Body != "" {
+		body = strings.NewReader(service.Body)
+	}
+
+	req, err := http.NewRequest(method, service.URL, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	for key, value := range service.Headers {
+		// The Host header is not sent from req.Header, it must be set on the request itself
+		if strings.EqualFold(key, "Host") {
+			req.Host = value
+			continue
+		}
+		req.Header.Set(key, value)
+	}
+
+	return req, nil
+}
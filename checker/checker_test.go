@@ -1,11 +1,14 @@
 package checker
 
 import (
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/0xReLogic/SENTINEL/config"
 )
 
 func TestServiceStatusString(t *testing.T) {
@@ -105,6 +108,92 @@ func TestCheckServiceDefaultTimeout(t *testing.T) {
 	}
 }
 
+func TestCheckRequestOptions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("Expected POST request, got %s", r.Method)
+		}
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			t.Errorf("Expected Authorization header 'Bearer secret', got '%s'", r.Header.Get("Authorization"))
+		}
+		if r.Host != "internal.example.com" {
+			t.Errorf("Expected Host 'internal.example.com', got '%s'", r.Host)
+		}
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != `{"jsonrpc":"2.0","method":"health"}` {
+			t.Errorf("Unexpected request body: %s", body)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	status := Check(config.Service{
+		Name:    "RPC",
+		URL:     server.URL,
+		Timeout: 2 * time.Second,
+		Method:  "post",
+		Headers: map[string]string{
+			"Authorization": "Bearer secret",
+			"Host":          "internal.example.com",
+		},
+		Body: `{"jsonrpc":"2.0","method":"health"}`,
+	})
+
+	if !status.IsUp {
+		t.Errorf("Expected service to be UP, got error: %v", status.Error)
+	}
+}
+
+func TestCheckTLSCertificate(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	// Trust the test server certificate for the duration of the test
+	defaultTransport := http.DefaultTransport
+	http.DefaultTransport = server.Client().Transport
+	defer func() { http.DefaultTransport = defaultTransport }()
+
+	status := Check(config.Service{Name: "Secure", URL: server.URL, Timeout: 2 * time.Second})
+	if !status.IsUp {
+		t.Fatalf("Expected service to be UP, got error: %v", status.Error)
+	}
+	if status.TLS == nil {
+		t.Fatal("Expected TLS certificate info for HTTPS service")
+	}
+
+	leaf := server.Certificate()
+	if !status.TLS.NotAfter.Equal(leaf.NotAfter) {
+		t.Errorf("Expected NotAfter %v, got %v", leaf.NotAfter, status.TLS.NotAfter)
+	}
+	if status.TLS.Issuer != leaf.Issuer.String() {
+		t.Errorf("Expected issuer %q, got %q", leaf.Issuer.String(), status.TLS.Issuer)
+	}
+	if len(status.TLS.DNSNames) != len(leaf.DNSNames) {
+		t.Errorf("Expected SANs %v, got %v", leaf.DNSNames, status.TLS.DNSNames)
+	}
+
+	if !status.TLS.ExpiresWithin(time.Until(leaf.NotAfter)+time.Hour, time.Now()) {
+		t.Error("Expected certificate to expire within the window")
+	}
+	if status.TLS.ExpiresWithin(time.Hour, time.Now()) {
+		t.Error("Expected certificate not to expire within an hour")
+	}
+}
+
+func TestCheckPlainHTTPHasNoTLS(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	status := Check(config.Service{Name: "Plain", URL: server.URL, Timeout: 2 * time.Second})
+	if status.TLS != nil {
+		t.Errorf("Expected no TLS info for plain HTTP, got %+v", status.TLS)
+	}
+}
+
 // Simple error implementation for testing
 type testError struct {
 	msg string
@@ -113,3 +202,28 @@ type testError struct {
 func (e *testError) Error() string {
 	return e.msg
 }
+
+func TestIsValidURL(t *testing.T) {
+	tests := []struct {
+		url   string
+		valid bool
+	}{
+		{"https://example.com", true},
+		{"http://example.com", true},
+		{"https://example.com:8080", true},
+		{"https://example.com/path", true},
+		{"ftp://example.com", false},
+		{"example.com", false},
+		{"", false},
+		{"https://", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			result := isValidURL(tt.url)
+			if result != tt.valid {
+				t.Errorf("isValidURL(%q) = %v, want %v", tt.url, result, tt.valid)
+			}
+		})
+	}
+}
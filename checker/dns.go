@@ -0,0 +1,196 @@
+package checker
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/0xReLogic/SENTINEL/config"
+)
+
+// DNSRecordTypes lists the record types supported by DNS checks
+var DNSRecordTypes = []string{"A", "AAAA", "CNAME", "MX", "TXT", "SRV"}
+
+func init() {
+	Register(config.TypeDNS, DNSChecker{})
+}
+
+// DNSChecker resolves the service host and asserts on the returned answers.
+// Response time is the query latency.
+type DNSChecker struct{}
+
+// Validate checks the host, record type, resolver and min_answers of a DNS service
+func (DNSChecker) Validate(service config.Service) []error {
+	var errors []error
+	if service.Host == "" {
+		errors = append(errors, fmt.Errorf("host is required"))
+	}
+	if service.RecordType != "" && !isValidRecordType(service.RecordType) {
+		errors = append(errors, fmt.Errorf("unsupported record_type '%s', expected one of %s",
+			service.RecordType, strings.Join(DNSRecordTypes, ", ")))
+	}
+	if service.Resolver != "" && !isValidResolver(service.Resolver) {
+		errors = append(errors, fmt.Errorf("invalid resolver '%s', expected host[:port]", service.Resolver))
+	}
+	if service.MinAnswers < 0 {
+		errors = append(errors, fmt.Errorf("min_answers must not be negative"))
+	}
+	return errors
+}
+
+// isValidResolver checks if a string is a host with an optional port
+func isValidResolver(resolver string) bool {
+	if _, _, err := net.SplitHostPort(resolver); err == nil {
+		return isValidHostPort(resolver)
+	}
+	// the port defaults to 53
+	return isValidHostPort(net.JoinHostPort(resolver, "53"))
+}
+
+// isValidRecordType checks if a DNS record type is supported by DNS checks
+func isValidRecordType(recordType string) bool {
+	for _, supported := range DNSRecordTypes {
+		if strings.EqualFold(recordType, supported) {
+			return true
+		}
+	}
+	return false
+}
+
+// Check queries the service record and returns the service status
+func (DNSChecker) Check(service config.Service) ServiceStatus {
+	result := ServiceStatus{
+		Name: service.Name,
+		URL:  service.Target(),
+	}
+
+	timeout := service.Timeout
+	if timeout <= 0 {
+		timeout = config.DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	// Record start time
+	startTime := time.Now()
+
+	answers, err := lookupRecords(ctx, newResolver(service.Resolver), service.RecordType, service.Host)
+
+	// Calculate query latency
+	result.ResponseTime = time.Since(startTime)
+
+	if err != nil {
+		result.IsUp = false
+		result.Error = err
+		return result
+	}
+
+	if err := checkAnswers(answers, service); err != nil {
+		result.IsUp = false
+		result.Error = fmt.Errorf("assertion failed: %w", err)
+		return result
+	}
+
+	result.IsUp = true
+	return result
+}
+
+// newResolver returns a resolver that sends every query to the given
+// server, or the system resolver if no server is configured
+func newResolver(server string) *net.Resolver {
+	if server == "" {
+		return net.DefaultResolver
+	}
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(server, "53")
+	}
+
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, network, server)
+		},
+	}
+}
+
+// lookupRecords queries a record type and returns the answers as strings
+func lookupRecords(ctx context.Context, resolver *net.Resolver, recordType, name string) ([]string, error) {
+	var answers []string
+
+	switch strings.ToUpper(recordType) {
+	case "", "A", "AAAA":
+		network := "ip4"
+		if strings.EqualFold(recordType, "AAAA") {
+			network = "ip6"
+		}
+		ips, err := resolver.LookupIP(ctx, network, name)
+		if err != nil {
+			return nil, err
+		}
+		for _, ip := range ips {
+			answers = append(answers, ip.String())
+		}
+	case "CNAME":
+		cname, err := resolver.LookupCNAME(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		answers = append(answers, strings.TrimSuffix(cname, "."))
+	case "MX":
+		records, err := resolver.LookupMX(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		for _, mx := range records {
+			answers = append(answers, strings.TrimSuffix(mx.Host, "."))
+		}
+	case "TXT":
+		records, err := resolver.LookupTXT(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		answers = append(answers, records...)
+	case "SRV":
+		_, records, err := resolver.LookupSRV(ctx, "", "", name)
+		if err != nil {
+			return nil, err
+		}
+		for _, srv := range records {
+			answers = append(answers, net.JoinHostPort(strings.TrimSuffix(srv.Target, "."), strconv.Itoa(int(srv.Port))))
+		}
+	default:
+		return nil, fmt.Errorf("unsupported record type %q", recordType)
+	}
+
+	return answers, nil
+}
+
+// checkAnswers verifies the expected answers and minimum answer count
+func checkAnswers(answers []string, service config.Service) error {
+	if len(answers) == 0 {
+		return fmt.Errorf("no %s records for %s", service.RecordType, service.Host)
+	}
+	if len(answers) < service.MinAnswers {
+		return fmt.Errorf("got %d %s records, expected at least %d", len(answers), service.RecordType, service.MinAnswers)
+	}
+
+	for _, expected := range service.ExpectAnswers {
+		found := false
+		for _, answer := range answers {
+			// Names are returned without the trailing dot of the fully qualified form
+			if strings.EqualFold(answer, expected) || strings.EqualFold(answer, strings.TrimSuffix(expected, ".")) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%s records %v do not contain %q", service.RecordType, answers, expected)
+		}
+	}
+
+	return nil
+}
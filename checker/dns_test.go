@@ -0,0 +1,185 @@
+package checker
+
+import (
+	"encoding/binary"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0xReLogic/SENTINEL/config"
+)
+
+// DNS record type codes used by the stub server
+const (
+	dnsTypeA   = 1
+	dnsTypeMX  = 15
+	dnsTypeTXT = 16
+)
+
+// stubRecord is an answer served by the stub DNS server, rdata is already encoded
+type stubRecord struct {
+	qtype uint16
+	rdata []byte
+}
+
+// startDNSStub starts a UDP DNS server on localhost that answers every
+// question with the records of the matching type
+func startDNSStub(t *testing.T, records []stubRecord) string {
+	t.Helper()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			if response := stubResponse(buf[:n], records); response != nil {
+				conn.WriteTo(response, addr)
+			}
+		}
+	}()
+
+	return conn.LocalAddr().String()
+}
+
+// stubResponse builds the response to a single-question DNS query
+func stubResponse(query []byte, records []stubRecord) []byte {
+	if len(query) < 12 {
+		return nil
+	}
+
+	// Skip the question name to find its type
+	end := 12
+	for end < len(query) && query[end] != 0 {
+		end += int(query[end]) + 1
+	}
+	end++
+	if end+4 > len(query) {
+		return nil
+	}
+	qtype := binary.BigEndian.Uint16(query[end:])
+	question := query[12 : end+4]
+
+	var answers [][]byte
+	for _, r := range records {
+		if r.qtype != qtype {
+			continue
+		}
+		answer := []byte{0xc0, 0x0c} // pointer to the question name
+		answer = binary.BigEndian.AppendUint16(answer, r.qtype)
+		answer = binary.BigEndian.AppendUint16(answer, 1) // class IN
+		answer = binary.BigEndian.AppendUint32(answer, 60)
+		answer = binary.BigEndian.AppendUint16(answer, uint16(len(r.rdata)))
+		answers = append(answers, append(answer, r.rdata...))
+	}
+
+	response := append([]byte{}, query[0:2]...)           // ID
+	response = append(response, 0x81, 0x80)               // standard response, no error
+	response = binary.BigEndian.AppendUint16(response, 1) // questions
+	response = binary.BigEndian.AppendUint16(response, uint16(len(answers)))
+	response = append(response, 0, 0, 0, 0) // authority and additional
+	response = append(response, question...)
+	for _, answer := range answers {
+		response = append(response, answer...)
+	}
+	return response
+}
+
+// encodeName encodes a domain name in DNS wire format
+func encodeName(name string) []byte {
+	var encoded []byte
+	for _, label := range strings.Split(strings.TrimSuffix(name, "."), ".") {
+		encoded = append(encoded, byte(len(label)))
+		encoded = append(encoded, label...)
+	}
+	return append(encoded, 0)
+}
+
+func mxRecord(preference uint16, host string) stubRecord {
+	rdata := binary.BigEndian.AppendUint16(nil, preference)
+	return stubRecord{qtype: dnsTypeMX, rdata: append(rdata, encodeName(host)...)}
+}
+
+func TestCheckDNS(t *testing.T) {
+	resolver := startDNSStub(t, []stubRecord{
+		{qtype: dnsTypeA, rdata: net.ParseIP("10.0.0.5").To4()},
+		{qtype: dnsTypeA, rdata: net.ParseIP("10.0.0.6").To4()},
+		mxRecord(10, "mx1.sentinel.test."),
+		mxRecord(20, "mx2.sentinel.test."),
+		{qtype: dnsTypeTXT, rdata: append([]byte{14}, "v=spf1 -all ok"...)},
+	})
+
+	tests := []struct {
+		name       string
+		recordType string
+		expect     []string
+		minAnswers int
+		wantUp     bool
+		wantErr    string
+	}{
+		{name: "A record present", recordType: "A", wantUp: true},
+		{name: "A contains address", recordType: "A", expect: []string{"10.0.0.5"}, wantUp: true},
+		{name: "A missing address", recordType: "A", expect: []string{"10.0.0.9"}, wantErr: "do not contain"},
+		{name: "MX count", recordType: "MX", minAnswers: 2, wantUp: true},
+		{name: "MX count too low", recordType: "MX", minAnswers: 3, wantErr: "at least 3"},
+		{name: "MX contains host", recordType: "mx", expect: []string{"mx2.sentinel.test."}, wantUp: true},
+		{name: "TXT contains value", recordType: "TXT", expect: []string{"v=spf1 -all ok"}, wantUp: true},
+		{name: "no AAAA records", recordType: "AAAA", wantErr: "sentinel.test"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := Check(config.Service{
+				Name:          "DNS",
+				Type:          config.TypeDNS,
+				Host:          "svc.sentinel.test",
+				Timeout:       2 * time.Second,
+				RecordType:    tt.recordType,
+				Resolver:      resolver,
+				ExpectAnswers: tt.expect,
+				MinAnswers:    tt.minAnswers,
+			})
+
+			if status.IsUp != tt.wantUp {
+				t.Fatalf("Expected IsUp=%v, got %v (error: %v)", tt.wantUp, status.IsUp, status.Error)
+			}
+			if tt.wantErr != "" && (status.Error == nil || !strings.Contains(status.Error.Error(), tt.wantErr)) {
+				t.Errorf("Expected error containing %q, got: %v", tt.wantErr, status.Error)
+			}
+			if !strings.HasPrefix(status.URL, "dns://"+resolver+"/svc.sentinel.test") {
+				t.Errorf("Expected DNS target URL, got '%s'", status.URL)
+			}
+		})
+	}
+}
+
+func TestCheckDNSResolverUnreachable(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	// A socket that never answers
+	defer conn.Close()
+
+	status := Check(config.Service{
+		Name:       "DNS",
+		Type:       config.TypeDNS,
+		Host:       "svc.sentinel.test",
+		Timeout:    300 * time.Millisecond,
+		RecordType: "A",
+		Resolver:   conn.LocalAddr().String(),
+	})
+	if status.IsUp {
+		t.Error("Expected DNS check against silent resolver to be DOWN")
+	}
+	if status.Error == nil {
+		t.Error("Expected a resolver error, got nil")
+	}
+}
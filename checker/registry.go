@@ -0,0 +1,123 @@
+package checker
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/0xReLogic/SENTINEL/config"
+)
+
+// Checker checks a single service and reports its status
+type Checker interface {
+	Check(service config.Service) ServiceStatus
+}
+
+// CheckerFunc adapts an ordinary function to the Checker interface
+type CheckerFunc func(service config.Service) ServiceStatus
+
+// Check calls f(service)
+func (f CheckerFunc) Check(service config.Service) ServiceStatus {
+	return f(service)
+}
+
+// Validator is implemented by checkers that validate their type specific
+// service settings. Each returned error describes one problem.
+type Validator interface {
+	Validate(service config.Service) []error
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Checker)
+)
+
+// Register makes a checker available for services of the given type.
+// It panics if the checker is nil or the type is already registered.
+func Register(serviceType string, c Checker) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if c == nil {
+		panic("checker: Register checker is nil")
+	}
+	if _, dup := registry[serviceType]; dup {
+		panic("checker: Register called twice for type " + serviceType)
+	}
+	registry[serviceType] = c
+}
+
+// Lookup returns the checker registered for a service type. An empty type
+// resolves to the default http checker.
+func Lookup(serviceType string) (Checker, bool) {
+	if serviceType == "" {
+		serviceType = config.TypeHTTP
+	}
+
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	c, ok := registry[serviceType]
+	return c, ok
+}
+
+// Types returns the sorted list of registered service types
+func Types() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	types := make([]string, 0, len(registry))
+	for t := range registry {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// Check runs the checker registered for the service type and returns the
+// service status. Failed attempts are retried with exponential backoff when
+// the service configures retries, as long as the next attempt would end
+// within the service interval, and slow successful checks are marked
+// degraded.
+func Check(service config.Service) ServiceStatus {
+	c, ok := Lookup(service.Type)
+	if !ok {
+		return ServiceStatus{
+			ID:    service.Key(),
+			Name:  service.Name,
+			URL:   service.Target(),
+			IsUp:  false,
+			Error: fmt.Errorf("unsupported service type %q", service.Type),
+		}
+	}
+
+	start := time.Now()
+	status := c.Check(service)
+	if service.Retries > 0 {
+		attempts := []Attempt{newAttempt(status)}
+		for retry := 0; retry < service.Retries && !status.IsUp; retry++ {
+			// A check running into the next one would stall the worker pool
+			delay := service.RetryWait(retry)
+			if service.Interval > 0 && time.Since(start)+delay+service.Timeout > service.Interval {
+				break
+			}
+			time.Sleep(delay)
+
+			status = c.Check(service)
+			attempts = append(attempts, newAttempt(status))
+		}
+		status.Attempts = attempts
+	}
+
+	status.ID = service.Key()
+	status.Degraded = status.IsUp && service.DegradedThreshold > 0 && status.ResponseTime >= service.DegradedThreshold
+	return status
+}
+
+func newAttempt(status ServiceStatus) Attempt {
+	return Attempt{
+		ResponseTime: status.ResponseTime,
+		StatusCode:   status.StatusCode,
+		Error:        status.Error,
+	}
+}
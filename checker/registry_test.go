@@ -0,0 +1,259 @@
+package checker
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0xReLogic/SENTINEL/config"
+)
+
+func TestBuiltinCheckersRegistered(t *testing.T) {
+	for _, serviceType := range []string{config.TypeHTTP, config.TypeTCP, config.TypeDNS} {
+		if _, ok := Lookup(serviceType); !ok {
+			t.Errorf("Expected built-in checker for type %q", serviceType)
+		}
+	}
+
+	c, ok := Lookup("")
+	if !ok {
+		t.Fatal("Expected empty type to resolve to the http checker")
+	}
+	if _, isHTTP := c.(HTTPChecker); !isHTTP {
+		t.Errorf("Expected empty type to resolve to HTTPChecker, got %T", c)
+	}
+}
+
+func TestBuiltinValidators(t *testing.T) {
+	tests := []struct {
+		service config.Service
+		wantErr string
+	}{
+		{service: config.Service{URL: "https://example.com", Method: "post"}},
+		{service: config.Service{URL: "example.com"}, wantErr: "invalid URL format 'example.com'"},
+		{service: config.Service{Type: config.TypeTCP, Host: "db.internal:5432"}},
+		{service: config.Service{Type: config.TypeTCP, Host: "db.internal"}, wantErr: "invalid host 'db.internal', expected host:port"},
+		{service: config.Service{Type: config.TypeDNS, Host: "example.com", RecordType: "mx", Resolver: "1.1.1.1"}},
+		{service: config.Service{Type: config.TypeDNS, Host: "example.com", RecordType: "NS"}, wantErr: "unsupported record_type 'NS'"},
+	}
+
+	for _, tt := range tests {
+		c, _ := Lookup(tt.service.Type)
+		validator, ok := c.(Validator)
+		if !ok {
+			t.Fatalf("Expected the %q checker to implement Validator", tt.service.Type)
+		}
+		errs := validator.Validate(tt.service)
+		if tt.wantErr == "" {
+			if len(errs) != 0 {
+				t.Errorf("Expected no errors for %+v, got %v", tt.service, errs)
+			}
+			continue
+		}
+		if len(errs) != 1 || !strings.Contains(errs[0].Error(), tt.wantErr) {
+			t.Errorf("Expected error containing %q, got %v", tt.wantErr, errs)
+		}
+	}
+}
+
+func TestRegisterCustomChecker(t *testing.T) {
+	Register("registry-test", CheckerFunc(func(service config.Service) ServiceStatus {
+		return ServiceStatus{Name: service.Name, URL: "custom://" + service.Host, IsUp: true}
+	}))
+
+	status := Check(config.Service{Name: "Custom", Type: "registry-test", Host: "queue"})
+	if !status.IsUp {
+		t.Errorf("Expected custom checker to report UP, got error: %v", status.Error)
+	}
+	if status.URL != "custom://queue" {
+		t.Errorf("Expected custom checker result, got URL '%s'", status.URL)
+	}
+
+	found := false
+	for _, serviceType := range Types() {
+		if serviceType == "registry-test" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected Types() to include registry-test, got %v", Types())
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected Register to panic on duplicate type")
+		}
+	}()
+	Register("registry-test", CheckerFunc(func(service config.Service) ServiceStatus {
+		return ServiceStatus{}
+	}))
+}
+
+func TestCheckUnknownType(t *testing.T) {
+	status := Check(config.Service{Name: "Unknown", Type: "carrier-pigeon"})
+	if status.IsUp {
+		t.Error("Expected unknown service type to be DOWN")
+	}
+	if status.Error == nil {
+		t.Error("Expected an error for unknown service type")
+	}
+}
+
+// flakyChecker fails until it has been called succeedOn times
+type flakyChecker struct {
+	calls     int
+	succeedOn int
+}
+
+func (f *flakyChecker) Check(service config.Service) ServiceStatus {
+	f.calls++
+	if f.calls < f.succeedOn {
+		return ServiceStatus{Name: service.Name, Error: fmt.Errorf("attempt %d failed", f.calls)}
+	}
+	return ServiceStatus{Name: service.Name, IsUp: true, StatusCode: 200}
+}
+
+func TestCheckRetries(t *testing.T) {
+	flaky := &flakyChecker{}
+	Register("retry-test", flaky)
+
+	tests := []struct {
+		name         string
+		retries      int
+		succeedOn    int
+		wantUp       bool
+		wantAttempts int
+		wantSummary  string
+	}{
+		{name: "no retries", retries: 0, succeedOn: 2, wantUp: false, wantAttempts: 0},
+		{name: "first attempt succeeds", retries: 3, succeedOn: 1, wantUp: true, wantAttempts: 1},
+		{name: "succeeds on third attempt", retries: 3, succeedOn: 3, wantUp: true, wantAttempts: 3, wantSummary: "succeeded on attempt 3"},
+		{name: "retries exhausted", retries: 2, succeedOn: 5, wantUp: false, wantAttempts: 3, wantSummary: "failed after 3 attempts"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flaky.calls = 0
+			flaky.succeedOn = tt.succeedOn
+
+			status := Check(config.Service{
+				Name:       "Flaky",
+				Type:       "retry-test",
+				Retries:    tt.retries,
+				RetryDelay: time.Millisecond,
+			})
+
+			if status.IsUp != tt.wantUp {
+				t.Errorf("Expected IsUp=%v, got %v", tt.wantUp, status.IsUp)
+			}
+			if len(status.Attempts) != tt.wantAttempts {
+				t.Fatalf("Expected %d attempts, got %d", tt.wantAttempts, len(status.Attempts))
+			}
+			if got := status.AttemptSummary(); got != tt.wantSummary {
+				t.Errorf("Expected summary %q, got %q", tt.wantSummary, got)
+			}
+			for i, attempt := range status.Attempts[:max(len(status.Attempts)-1, 0)] {
+				if attempt.Error == nil {
+					t.Errorf("Expected attempt %d to keep its error", i+1)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckRetryBackoff(t *testing.T) {
+	Register("backoff-test", &flakyChecker{succeedOn: 10})
+
+	start := time.Now()
+	status := Check(config.Service{
+		Name:       "Backoff",
+		Type:       "backoff-test",
+		Retries:    3,
+		RetryDelay: 20 * time.Millisecond,
+	})
+	elapsed := time.Since(start)
+
+	if status.IsUp {
+		t.Error("Expected service to stay DOWN")
+	}
+	// Delays of 20ms, 40ms and 80ms between the four attempts
+	if elapsed < 140*time.Millisecond {
+		t.Errorf("Expected exponential backoff of at least 140ms, took %v", elapsed)
+	}
+}
+
+func TestCheckRetriesWithinInterval(t *testing.T) {
+	Register("interval-test", &flakyChecker{succeedOn: 10})
+
+	// The second retry would wait 40ms after 20ms and end after the interval
+	status := Check(config.Service{
+		Name:       "Interval",
+		Type:       "interval-test",
+		Interval:   50 * time.Millisecond,
+		Retries:    5,
+		RetryDelay: 20 * time.Millisecond,
+	})
+	if status.IsUp || len(status.Attempts) != 2 {
+		t.Errorf("Expected 2 failed attempts within the interval, got %d", len(status.Attempts))
+	}
+}
+
+func TestCheckDegraded(t *testing.T) {
+	Register("degraded-test", CheckerFunc(func(service config.Service) ServiceStatus {
+		return ServiceStatus{Name: service.Name, IsUp: service.Host != "down", ResponseTime: 200 * time.Millisecond}
+	}))
+
+	tests := []struct {
+		name       string
+		host       string
+		threshold  time.Duration
+		wantResult string
+	}{
+		{name: "no threshold", threshold: 0, wantResult: StateUp},
+		{name: "faster than threshold", threshold: time.Second, wantResult: StateUp},
+		{name: "slower than threshold", threshold: 100 * time.Millisecond, wantResult: StateDegraded},
+		{name: "down is never degraded", host: "down", threshold: 100 * time.Millisecond, wantResult: StateDown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := Check(config.Service{Name: "Slow", Type: "degraded-test", Host: tt.host, DegradedThreshold: tt.threshold})
+			if status.ID != "slow" {
+				t.Errorf("Expected ID derived from name 'slow', got %q", status.ID)
+			}
+			if got := status.Result(); got != tt.wantResult {
+				t.Errorf("Expected result %s, got %s", tt.wantResult, got)
+			}
+			if !strings.Contains(status.String(), "["+tt.wantResult+"]") {
+				t.Errorf("Expected status string to show %s, got %s", tt.wantResult, status.String())
+			}
+		})
+	}
+}
+
+// validatingChecker is a custom checker that validates its settings
+type validatingChecker struct{}
+
+func (validatingChecker) Check(service config.Service) ServiceStatus {
+	return ServiceStatus{Name: service.Name, IsUp: true}
+}
+
+func (validatingChecker) Validate(service config.Service) []error {
+	if service.Host == "" {
+		return []error{errors.New("host is required")}
+	}
+	return nil
+}
+
+func TestValidatorInterface(t *testing.T) {
+	var c Checker = validatingChecker{}
+	validator, ok := c.(Validator)
+	if !ok {
+		t.Fatal("Expected validatingChecker to implement Validator")
+	}
+	if errs := validator.Validate(config.Service{Name: "Custom"}); len(errs) != 1 {
+		t.Errorf("Expected 1 validation error, got %v", errs)
+	}
+}
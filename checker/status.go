@@ -0,0 +1,105 @@
+package checker
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// StatusMatcher decides whether an HTTP status code counts as a successful check
+type StatusMatcher []statusRange
+
+// statusRange is an inclusive range of HTTP status codes
+type statusRange struct {
+	min int
+	max int
+}
+
+// defaultStatusMatcher accepts 2xx and 3xx responses
+var defaultStatusMatcher = StatusMatcher{{min: 200, max: 399}}
+
+// ParseStatusRules parses expected status rules into a StatusMatcher.
+// Each rule is an exact code ("200"), an inclusive range ("200-299") or a
+// class ("2xx"). Several rules may also be separated by commas in one entry.
+// An empty rule list accepts 2xx and 3xx responses.
+func ParseStatusRules(rules []string) (StatusMatcher, error) {
+	var matcher StatusMatcher
+
+	for _, entry := range rules {
+		for _, rule := range strings.Split(entry, ",") {
+			rule = strings.TrimSpace(rule)
+			if rule == "" {
+				return nil, fmt.Errorf("empty status rule in %q", entry)
+			}
+
+			r, err := parseStatusRule(rule)
+			if err != nil {
+				return nil, err
+			}
+			matcher = append(matcher, r)
+		}
+	}
+
+	if len(matcher) == 0 {
+		return defaultStatusMatcher, nil
+	}
+	return matcher, nil
+}
+
+// Match reports whether the status code satisfies any of the rules
+func (m StatusMatcher) Match(code int) bool {
+	for _, r := range m {
+		if code >= r.min && code <= r.max {
+			return true
+		}
+	}
+	return false
+}
+
+func parseStatusRule(rule string) (statusRange, error) {
+	lower := strings.ToLower(rule)
+
+	// Status class, e.g. 2xx
+	if len(lower) == 3 && strings.HasSuffix(lower, "xx") {
+		class, err := strconv.Atoi(lower[:1])
+		if err != nil || class < 1 || class > 5 {
+			return statusRange{}, fmt.Errorf("invalid status class %q", rule)
+		}
+		return statusRange{min: class * 100, max: class*100 + 99}, nil
+	}
+
+	// Inclusive range, e.g. 200-299
+	if from, to, found := strings.Cut(lower, "-"); found {
+		min, err := parseStatusCode(from)
+		if err != nil {
+			return statusRange{}, fmt.Errorf("invalid status range %q: %w", rule, err)
+		}
+		max, err := parseStatusCode(to)
+		if err != nil {
+			return statusRange{}, fmt.Errorf("invalid status range %q: %w", rule, err)
+		}
+		if min > max {
+			return statusRange{}, fmt.Errorf("invalid status range %q: start is greater than end", rule)
+		}
+		return statusRange{min: min, max: max}, nil
+	}
+
+	// Exact code, e.g. 401
+	code, err := parseStatusCode(lower)
+	if err != nil {
+		return statusRange{}, fmt.Errorf("invalid status code %q: %w", rule, err)
+	}
+	return statusRange{min: code, max: code}, nil
+}
+
+func parseStatusCode(s string) (int, error) {
+	code, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil {
+		return 0, errors.New("not a number")
+	}
+	if code < 100 || code > 599 {
+		return 0, errors.New("must be between 100 and 599")
+	}
+	return code, nil
+}
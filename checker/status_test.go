@@ -0,0 +1,121 @@
+package checker
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/0xReLogic/SENTINEL/config"
+)
+
+func TestParseStatusRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		rules   []string
+		accept  []int
+		reject  []int
+		wantErr bool
+	}{
+		{
+			name:   "default range",
+			rules:  nil,
+			accept: []int{200, 204, 301, 399},
+			reject: []int{199, 400, 401, 500},
+		},
+		{
+			name:   "exact code",
+			rules:  []string{"401"},
+			accept: []int{401},
+			reject: []int{200, 400, 403},
+		},
+		{
+			name:   "status class",
+			rules:  []string{"2xx"},
+			accept: []int{200, 299},
+			reject: []int{301, 199},
+		},
+		{
+			name:   "inclusive range",
+			rules:  []string{"200-204"},
+			accept: []int{200, 204},
+			reject: []int{205, 301},
+		},
+		{
+			name:   "mixed rules and comma separated",
+			rules:  []string{"2XX", "401, 418"},
+			accept: []int{201, 401, 418},
+			reject: []int{302, 404},
+		},
+		{name: "not a number", rules: []string{"abc"}, wantErr: true},
+		{name: "out of range code", rules: []string{"999"}, wantErr: true},
+		{name: "invalid class", rules: []string{"9xx"}, wantErr: true},
+		{name: "reversed range", rules: []string{"299-200"}, wantErr: true},
+		{name: "empty rule", rules: []string{"200,"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matcher, err := ParseStatusRules(tt.rules)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Expected error for rules %v, got nil", tt.rules)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseStatusRules(%v) failed: %v", tt.rules, err)
+			}
+			for _, code := range tt.accept {
+				if !matcher.Match(code) {
+					t.Errorf("Expected %d to match %v", code, tt.rules)
+				}
+			}
+			for _, code := range tt.reject {
+				if matcher.Match(code) {
+					t.Errorf("Expected %d not to match %v", code, tt.rules)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckExpectedStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/auth":
+			w.WriteHeader(http.StatusUnauthorized)
+		case "/redirect":
+			http.Redirect(w, r, "/ok", http.StatusFound)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		path     string
+		expected config.StatusRules
+		wantUp   bool
+	}{
+		{name: "401 accepted", path: "/auth", expected: config.StatusRules{"401"}, wantUp: true},
+		{name: "401 rejected by default", path: "/auth", wantUp: false},
+		{name: "redirect rejected by 2xx", path: "/redirect", expected: config.StatusRules{"2xx"}, wantUp: false},
+		{name: "redirect accepted by 302", path: "/redirect", expected: config.StatusRules{"302"}, wantUp: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := Check(config.Service{
+				Name:           "Test",
+				URL:            server.URL + tt.path,
+				Timeout:        2 * time.Second,
+				ExpectedStatus: tt.expected,
+			})
+			if status.IsUp != tt.wantUp {
+				t.Errorf("Expected IsUp=%v, got %v (HTTP %d, error: %v)", tt.wantUp, status.IsUp, status.StatusCode, status.Error)
+			}
+		})
+	}
+}
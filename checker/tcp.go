@@ -0,0 +1,117 @@
+package checker
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/0xReLogic/SENTINEL/config"
+)
+
+// maxBannerSize caps how much of a TCP reply is read while waiting for the expected banner
+const maxBannerSize = 64 * 1024
+
+func init() {
+	Register(config.TypeTCP, TCPChecker{})
+}
+
+// TCPChecker connects to the service host and, when configured, sends a payload
+// and waits for the expected banner. Response time is the connect latency.
+type TCPChecker struct{}
+
+// Validate checks that a TCP service has a host:port to connect to
+func (TCPChecker) Validate(service config.Service) []error {
+	if service.Host == "" {
+		return []error{fmt.Errorf("host is required")}
+	}
+	if !isValidHostPort(service.Host) {
+		return []error{fmt.Errorf("invalid host '%s', expected host:port", service.Host)}
+	}
+	return nil
+}
+
+// isValidHostPort checks if a string is a host:port pair with a valid port
+func isValidHostPort(address string) bool {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil || host == "" {
+		return false
+	}
+	p, err := strconv.Atoi(port)
+	return err == nil && p > 0 && p <= 65535
+}
+
+// Check connects to the service host and returns the service status
+func (TCPChecker) Check(service config.Service) ServiceStatus {
+	result := ServiceStatus{
+		Name: service.Name,
+		URL:  service.Target(),
+	}
+
+	timeout := service.Timeout
+	if timeout <= 0 {
+		timeout = config.DefaultTimeout
+	}
+	deadline := time.Now().Add(timeout)
+
+	// Record start time
+	startTime := time.Now()
+
+	conn, err := net.DialTimeout("tcp", service.Host, timeout)
+
+	// Calculate connect latency
+	result.ResponseTime = time.Since(startTime)
+
+	if err != nil {
+		result.IsUp = false
+		result.Error = err
+		return result
+	}
+	defer conn.Close()
+
+	if err := conn.SetDeadline(deadline); err != nil {
+		result.IsUp = false
+		result.Error = err
+		return result
+	}
+
+	if service.Send != "" {
+		if _, err := conn.Write([]byte(service.Send)); err != nil {
+			result.IsUp = false
+			result.Error = fmt.Errorf("failed to send payload: %w", err)
+			return result
+		}
+	}
+
+	if service.Expect != "" {
+		if err := expectBanner(conn, []byte(service.Expect)); err != nil {
+			result.IsUp = false
+			result.Error = err
+			return result
+		}
+	}
+
+	result.IsUp = true
+	return result
+}
+
+// expectBanner reads from the connection until the expected bytes show up,
+// the peer closes the connection or the deadline passes
+func expectBanner(conn net.Conn, expect []byte) error {
+	var received []byte
+	buf := make([]byte, 4096)
+
+	for len(received) < maxBannerSize {
+		n, err := conn.Read(buf)
+		received = append(received, buf[:n]...)
+		if bytes.Contains(received, expect) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("expected banner %q not received: %w", expect, err)
+		}
+	}
+
+	return fmt.Errorf("expected banner %q not received in first %d bytes", expect, maxBannerSize)
+}
@@ -0,0 +1,137 @@
+package checker
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0xReLogic/SENTINEL/config"
+)
+
+// startTCPServer starts a TCP server that runs handle for every accepted connection
+func startTCPServer(t *testing.T, handle func(conn net.Conn)) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				handle(conn)
+			}()
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+func TestCheckTCPConnect(t *testing.T) {
+	addr := startTCPServer(t, func(conn net.Conn) {})
+
+	status := Check(config.Service{Name: "Database", Type: config.TypeTCP, Host: addr, Timeout: 2 * time.Second})
+	if !status.IsUp {
+		t.Fatalf("Expected TCP service to be UP, got error: %v", status.Error)
+	}
+	if status.URL != "tcp://"+addr {
+		t.Errorf("Expected URL 'tcp://%s', got '%s'", addr, status.URL)
+	}
+	if status.StatusCode != 0 {
+		t.Errorf("Expected no status code for TCP check, got %d", status.StatusCode)
+	}
+	if strings.Contains(status.String(), "HTTP") {
+		t.Errorf("Expected TCP status string without HTTP code, got: %s", status.String())
+	}
+}
+
+func TestCheckTCPConnectionRefused(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	status := Check(config.Service{Name: "Database", Type: config.TypeTCP, Host: addr, Timeout: 2 * time.Second})
+	if status.IsUp {
+		t.Error("Expected TCP service to be DOWN")
+	}
+	if status.Error == nil {
+		t.Error("Expected a connection error, got nil")
+	}
+}
+
+func TestCheckTCPSendExpect(t *testing.T) {
+	addr := startTCPServer(t, func(conn net.Conn) {
+		line, err := bufio.NewReader(conn).ReadString('\n')
+		if err != nil {
+			return
+		}
+		if line == "PING\r\n" {
+			conn.Write([]byte("+PONG\r\n"))
+		} else {
+			conn.Write([]byte("-ERR unknown command\r\n"))
+		}
+	})
+
+	status := Check(config.Service{Name: "Redis", Type: config.TypeTCP, Host: addr, Timeout: 2 * time.Second,
+		Send: "PING\r\n", Expect: "+PONG"})
+	if !status.IsUp {
+		t.Errorf("Expected TCP service to be UP, got error: %v", status.Error)
+	}
+
+	status = Check(config.Service{Name: "Redis", Type: config.TypeTCP, Host: addr, Timeout: 2 * time.Second,
+		Send: "HELLO\r\n", Expect: "+PONG"})
+	if status.IsUp {
+		t.Error("Expected TCP service with wrong banner to be DOWN")
+	}
+	if status.Error == nil || !strings.Contains(status.Error.Error(), "expected banner") {
+		t.Errorf("Expected banner error, got: %v", status.Error)
+	}
+}
+
+func TestCheckTCPExpectTimeout(t *testing.T) {
+	addr := startTCPServer(t, func(conn net.Conn) {
+		time.Sleep(time.Second)
+	})
+
+	status := Check(config.Service{Name: "Silent", Type: config.TypeTCP, Host: addr, Timeout: 200 * time.Millisecond,
+		Expect: "SSH-"})
+	if status.IsUp {
+		t.Error("Expected silent TCP service to be DOWN")
+	}
+}
+
+func TestIsValidHostPort(t *testing.T) {
+	tests := []struct {
+		address string
+		valid   bool
+	}{
+		{"db.internal:5432", true},
+		{"127.0.0.1:6379", true},
+		{"[::1]:22", true},
+		{"db.internal", false},
+		{":5432", false},
+		{"db.internal:0", false},
+		{"db.internal:70000", false},
+		{"db.internal:ssh", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			result := isValidHostPort(tt.address)
+			if result != tt.valid {
+				t.Errorf("isValidHostPort(%q) = %v, want %v", tt.address, result, tt.valid)
+			}
+		})
+	}
+}
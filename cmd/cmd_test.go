@@ -1,20 +1,29 @@
 package cmd
 
 import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/0xReLogic/SENTINEL/checker"
 	"github.com/0xReLogic/SENTINEL/config"
+	"github.com/0xReLogic/SENTINEL/notifier"
+	"github.com/0xReLogic/SENTINEL/storage"
 	"github.com/spf13/cobra"
 )
 
 const (
 	testExampleURL = "https://example.com"
+	testServiceID  = "example"
 	testStatus200  = "/status/200"
 )
 
@@ -360,6 +369,155 @@ func TestValidateServices(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "negative failure threshold",
+			services: []config.Service{
+				{Name: "Test", URL: testExampleURL, Interval: config.DefaultInterval, Timeout: config.DefaultTimeout, FailureThreshold: -1},
+			},
+			wantErr: true,
+		},
+		{
+			name: "duplicate ids",
+			services: []config.Service{
+				{Name: "API", URL: testExampleURL, Interval: config.DefaultInterval, Timeout: config.DefaultTimeout},
+				{Name: "Api", URL: testExampleURL, Interval: config.DefaultInterval, Timeout: config.DefaultTimeout},
+			},
+			wantErr: true,
+		},
+		{
+			name: "same url with distinct ids",
+			services: []config.Service{
+				{ID: "api-public", Name: "API", URL: testExampleURL, Interval: config.DefaultInterval, Timeout: config.DefaultTimeout},
+				{ID: "api-admin", Name: "API", URL: testExampleURL, Interval: config.DefaultInterval, Timeout: config.DefaultTimeout},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid id",
+			services: []config.Service{
+				{ID: "my api", Name: "API", URL: testExampleURL, Interval: config.DefaultInterval, Timeout: config.DefaultTimeout},
+			},
+			wantErr: true,
+		},
+		{
+			name: "name without letters or digits",
+			services: []config.Service{
+				{Name: "???", URL: testExampleURL, Interval: config.DefaultInterval, Timeout: config.DefaultTimeout},
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative retries",
+			services: []config.Service{
+				{Name: "Test", URL: testExampleURL, Interval: config.DefaultInterval, Timeout: config.DefaultTimeout, Retries: -1},
+			},
+			wantErr: true,
+		},
+		{
+			name: "retries with delay",
+			services: []config.Service{
+				{Name: "Test", URL: testExampleURL, Interval: config.DefaultInterval, Timeout: config.DefaultTimeout, Retries: 2, RetryDelay: time.Second},
+			},
+			wantErr: false,
+		},
+		{
+			name: "retries longer than interval",
+			services: []config.Service{
+				{Name: "Test", URL: testExampleURL, Interval: config.DefaultInterval, Timeout: config.DefaultTimeout, Retries: 5, RetryDelay: time.Second},
+			},
+			wantErr: true,
+		},
+		{
+			name: "custom method",
+			services: []config.Service{
+				{Name: "Test", URL: testExampleURL, Interval: config.DefaultInterval, Timeout: config.DefaultTimeout, Method: "post"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "valid expected status",
+			services: []config.Service{
+				{Name: "Test", URL: testExampleURL, Interval: config.DefaultInterval, Timeout: config.DefaultTimeout, ExpectedStatus: config.StatusRules{"2xx", "401"}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "malformed expected status",
+			services: []config.Service{
+				{Name: "Test", URL: testExampleURL, Interval: config.DefaultInterval, Timeout: config.DefaultTimeout, ExpectedStatus: config.StatusRules{"2xy"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "malformed assertion",
+			services: []config.Service{
+				{Name: "Test", URL: testExampleURL, Interval: config.DefaultInterval, Timeout: config.DefaultTimeout, Assertions: []config.Assertion{{JSON: "$.status ="}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid tcp service",
+			services: []config.Service{
+				{Name: "DB", Type: config.TypeTCP, Host: "db.internal:5432", Interval: config.DefaultInterval, Timeout: config.DefaultTimeout},
+			},
+			wantErr: false,
+		},
+		{
+			name: "tcp service without host",
+			services: []config.Service{
+				{Name: "DB", Type: config.TypeTCP, Interval: config.DefaultInterval, Timeout: config.DefaultTimeout},
+			},
+			wantErr: true,
+		},
+		{
+			name: "tcp service without port",
+			services: []config.Service{
+				{Name: "DB", Type: config.TypeTCP, Host: "db.internal", Interval: config.DefaultInterval, Timeout: config.DefaultTimeout},
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid dns service",
+			services: []config.Service{
+				{Name: "MX", Type: config.TypeDNS, Host: "example.com", RecordType: "MX", Resolver: "1.1.1.1", MinAnswers: 2, Interval: config.DefaultInterval, Timeout: config.DefaultTimeout},
+			},
+			wantErr: false,
+		},
+		{
+			name: "dns service with unsupported record type",
+			services: []config.Service{
+				{Name: "NS", Type: config.TypeDNS, Host: "example.com", RecordType: "NS", Interval: config.DefaultInterval, Timeout: config.DefaultTimeout},
+			},
+			wantErr: true,
+		},
+		{
+			name: "dns service without host",
+			services: []config.Service{
+				{Name: "A", Type: config.TypeDNS, RecordType: "A", Interval: config.DefaultInterval, Timeout: config.DefaultTimeout},
+			},
+			wantErr: true,
+		},
+		{
+			name: "dns service with invalid resolver",
+			services: []config.Service{
+				{Name: "A", Type: config.TypeDNS, Host: "example.com", Resolver: "1.1.1.1:dns", Interval: config.DefaultInterval, Timeout: config.DefaultTimeout},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unsupported type",
+			services: []config.Service{
+				{Name: "Test", Type: "icmp", URL: testExampleURL, Interval: config.DefaultInterval, Timeout: config.DefaultTimeout},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unsupported method",
+			services: []config.Service{
+				{Name: "Test", URL: testExampleURL, Interval: config.DefaultInterval, Timeout: config.DefaultTimeout, Method: "FETCH"},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -373,28 +531,61 @@ func TestValidateServices(t *testing.T) {
 	}
 }
 
-func TestIsValidURL(t *testing.T) {
-	tests := []struct {
-		url   string
-		valid bool
-	}{
-		{testExampleURL, true},
-		{"http://example.com", true},
-		{"https://example.com:8080", true},
-		{"https://example.com/path", true},
-		{"ftp://example.com", false},
-		{"example.com", false},
-		{"", false},
-		{"https://", false},
+// customChecker is an in-house check type registered from outside the command layer
+type customChecker struct{}
+
+func (customChecker) Check(service config.Service) checker.ServiceStatus {
+	return checker.ServiceStatus{Name: service.Name, URL: service.Host, IsUp: true}
+}
+
+func (customChecker) Validate(service config.Service) []error {
+	if service.Host == "" {
+		return []error{errors.New("host is required")}
 	}
+	return nil
+}
 
-	for _, tt := range tests {
-		t.Run(tt.url, func(t *testing.T) {
-			result := isValidURL(tt.url)
-			if result != tt.valid {
-				t.Errorf("isValidURL(%q) = %v, want %v", tt.url, result, tt.valid)
-			}
-		})
+func TestValidateRegisteredService(t *testing.T) {
+	checker.Register("cmd-test-custom", customChecker{})
+
+	valid := []config.Service{
+		{Name: "Queue", Type: "cmd-test-custom", Host: "queue.internal", Interval: config.DefaultInterval, Timeout: config.DefaultTimeout},
+	}
+	if errs := validateServices(valid); len(errs) != 0 {
+		t.Errorf("Expected registered custom service to be valid, got %v", errs)
+	}
+
+	invalid := []config.Service{
+		{Name: "Queue", Type: "cmd-test-custom", Interval: config.DefaultInterval, Timeout: config.DefaultTimeout},
+	}
+	errs := validateServices(invalid)
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "service #1 (Queue): host is required") {
+		t.Errorf("Expected custom validator error, got %v", errs)
+	}
+
+	cfg := &config.Config{Services: valid}
+	if !runChecksAndGetStatus(cfg, NewStateManager(), nil) {
+		t.Error("Expected custom checker to be used by runChecksAndGetStatus")
+	}
+}
+
+func TestValidateNotifications(t *testing.T) {
+	cfg := config.NotificationConfig{
+		Discord: config.DiscordConfig{Enabled: true, WebhookURL: testExampleURL},
+		Slack:   config.SlackConfig{Enabled: true},
+		Webhook: config.WebhookConfig{Enabled: true, Method: "DELETE"},
+	}
+	errs := validateNotifications(cfg)
+	if len(errs) != 3 || errs[0].Error() != "notifications.slack: webhook_url is required" ||
+		errs[1].Error() != "notifications.webhook: url is required" ||
+		!strings.Contains(errs[2].Error(), "notifications.webhook: unsupported method 'DELETE'") {
+		t.Errorf("Expected a webhook_url error for slack and url and method errors for the webhook, got %v", errs)
+	}
+
+	cfg.Slack.Enabled = false
+	cfg.Webhook.Enabled = false
+	if errs := validateNotifications(cfg); len(errs) != 0 {
+		t.Errorf("Expected disabled channels not to be validated, got %v", errs)
 	}
 }
 
@@ -487,3 +678,1076 @@ func TestRunChecksAndGetStatus(t *testing.T) {
 		})
 	}
 }
+
+func TestProcessCertExpiry(t *testing.T) {
+	sm := NewStateManager()
+	service := config.Service{Name: "Secure", URL: testExampleURL, CertExpiryWarning: 14 * 24 * time.Hour}
+	expiring := checker.ServiceStatus{
+		Name: "Secure",
+		URL:  testExampleURL,
+		IsUp: true,
+		TLS:  &checker.CertificateInfo{NotAfter: time.Now().Add(7 * 24 * time.Hour)},
+	}
+
+	if action := sm.ProcessCertExpiry(expiring, service); action.Action != NotifyCertExpiring {
+		t.Errorf("Expected NotifyCertExpiring for expiring certificate, got %v", action.Action)
+	}
+	if action := sm.ProcessCertExpiry(expiring, service); action.Action != NoAction {
+		t.Errorf("Expected a single warning per certificate, got %v", action.Action)
+	}
+
+	renewed := expiring
+	renewed.TLS = &checker.CertificateInfo{NotAfter: time.Now().Add(90 * 24 * time.Hour)}
+	if action := sm.ProcessCertExpiry(renewed, service); action.Action != NoAction {
+		t.Errorf("Expected NoAction for renewed certificate, got %v", action.Action)
+	}
+
+	noWarning := service
+	noWarning.CertExpiryWarning = 0
+	if action := NewStateManager().ProcessCertExpiry(expiring, noWarning); action.Action != NoAction {
+		t.Errorf("Expected NoAction without cert_expiry_warning, got %v", action.Action)
+	}
+
+	plain := expiring
+	plain.TLS = nil
+	if action := NewStateManager().ProcessCertExpiry(plain, service); action.Action != NoAction {
+		t.Errorf("Expected NoAction for non-TLS status, got %v", action.Action)
+	}
+}
+
+// clearThrottle forgets every notification time so throttling does not
+// interfere with the transitions under test
+func clearThrottle(sm *StateManager) {
+	sm.services.Range(func(_, value any) bool {
+		t := value.(*serviceTracker)
+		t.mu.Lock()
+		t.lastNotification = make(map[string]time.Time)
+		t.mu.Unlock()
+		return true
+	})
+}
+
+func TestApplyThresholds(t *testing.T) {
+	sm := NewStateManager()
+	service := config.Service{Name: "Flaky", URL: testExampleURL, Interval: time.Minute, FailureThreshold: 3, SuccessThreshold: 2}
+	up := checker.ServiceStatus{ID: testServiceID, Name: "Flaky", URL: testExampleURL, IsUp: true}
+	down := checker.ServiceStatus{ID: testServiceID, Name: "Flaky", URL: testExampleURL, IsUp: false}
+	notifyOn := []string{"down", "recovery"}
+
+	steps := []struct {
+		status     checker.ServiceStatus
+		wantState  string
+		wantAction ActionType
+	}{
+		{up, checker.StateUp, NoAction},
+		{down, checker.StateUp, NoAction},
+		{down, checker.StateUp, NoAction},
+		{up, checker.StateUp, NoAction}, // a success resets the failure streak
+		{down, checker.StateUp, NoAction},
+		{down, checker.StateUp, NoAction},
+		{down, checker.StateDown, NotifyDown},
+		{up, checker.StateDown, NoAction},
+		{down, checker.StateDown, NoAction}, // a failure resets the success streak
+		{up, checker.StateDown, NoAction},
+		{up, checker.StateUp, NotifyRecovery},
+	}
+
+	for i, step := range steps {
+		status := sm.ApplyThresholds(step.status, service)
+		if status.State != step.wantState {
+			t.Errorf("step %d: expected state %s, got %s", i+1, step.wantState, status.State)
+		}
+		// bypass throttling so only the confirmed transitions are under test
+		clearThrottle(sm)
+		if action := sm.ChannelAction(config.ChannelTelegram, notifyOn, sm.Transition(status), status, service); action.Action != step.wantAction {
+			t.Errorf("step %d: expected action %v, got %v", i+1, step.wantAction, action.Action)
+		}
+	}
+}
+
+func TestApplyThresholdsDefault(t *testing.T) {
+	sm := NewStateManager()
+	service := config.Service{Name: "Default", URL: testExampleURL, FailureThreshold: 1, SuccessThreshold: 1}
+
+	sm.ApplyThresholds(checker.ServiceStatus{ID: testServiceID, URL: testExampleURL, IsUp: true}, service)
+	status := sm.ApplyThresholds(checker.ServiceStatus{ID: testServiceID, URL: testExampleURL, IsUp: false}, service)
+	if status.State != checker.StateDown {
+		t.Errorf("Expected a single failure to confirm DOWN with threshold 1, got %s", status.State)
+	}
+	if !strings.Contains(status.String(), "[DOWN]") {
+		t.Errorf("Expected status string to show DOWN, got %s", status.String())
+	}
+}
+
+func TestApplyThresholdsDegraded(t *testing.T) {
+	sm := NewStateManager()
+	service := config.Service{Name: "Slow", URL: testExampleURL, Interval: time.Minute, FailureThreshold: 2, SuccessThreshold: 1, DegradedThreshold: time.Second}
+	up := checker.ServiceStatus{ID: testServiceID, Name: "Slow", URL: testExampleURL, IsUp: true}
+	slow := checker.ServiceStatus{ID: testServiceID, Name: "Slow", URL: testExampleURL, IsUp: true, Degraded: true}
+	down := checker.ServiceStatus{ID: testServiceID, Name: "Slow", URL: testExampleURL, IsUp: false}
+	notifyOn := []string{"down", "recovery", "degraded"}
+
+	steps := []struct {
+		status     checker.ServiceStatus
+		wantState  string
+		wantAction ActionType
+	}{
+		{up, checker.StateUp, NoAction},
+		{slow, checker.StateUp, NoAction},
+		{slow, checker.StateDegraded, NotifyDegraded},
+		{down, checker.StateDegraded, NoAction},
+		{down, checker.StateDown, NotifyDown},
+		{slow, checker.StateDown, NoAction},
+		{up, checker.StateUp, NotifyRecovery},
+		{slow, checker.StateUp, NoAction},
+		{up, checker.StateUp, NoAction},
+	}
+
+	for i, step := range steps {
+		status := sm.ApplyThresholds(step.status, service)
+		if status.State != step.wantState {
+			t.Errorf("step %d: expected state %s, got %s", i+1, step.wantState, status.State)
+		}
+		clearThrottle(sm)
+		if action := sm.ChannelAction(config.ChannelTelegram, notifyOn, sm.Transition(status), status, service); action.Action != step.wantAction {
+			t.Errorf("step %d: expected action %v, got %v", i+1, step.wantAction, action.Action)
+		}
+	}
+
+	// Without "degraded" in notify_on a slow service stays silent
+	sm = NewStateManager()
+	quiet := []string{"down", "recovery"}
+	status := sm.ApplyThresholds(up, service)
+	sm.ChannelAction(config.ChannelTelegram, quiet, sm.Transition(status), status, service)
+	sm.ApplyThresholds(slow, service)
+	status = sm.ApplyThresholds(slow, service)
+	if action := sm.ChannelAction(config.ChannelTelegram, quiet, sm.Transition(status), status, service); action.Action != NoAction {
+		t.Errorf("Expected no action without degraded in notify_on, got %v", action.Action)
+	}
+}
+
+func TestTransitionFansOutToChannels(t *testing.T) {
+	sm := NewStateManager()
+	service := config.Service{Name: "Shared", URL: testExampleURL, Interval: time.Minute}
+	up := checker.ServiceStatus{ID: testServiceID, Name: "Shared", URL: testExampleURL, IsUp: true}
+	down := checker.ServiceStatus{ID: testServiceID, Name: "Shared", URL: testExampleURL, IsUp: false}
+	telegramOn := []string{"down", "recovery"}
+	discordOn := []string{"down"}
+
+	steps := []struct {
+		status       checker.ServiceStatus
+		wantTelegram ActionType
+		wantDiscord  ActionType
+	}{
+		{up, NoAction, NoAction},
+		{down, NotifyDown, NotifyDown},
+		{down, NoAction, NoAction},
+		{up, NotifyRecovery, NoAction}, // recovery is not in Discord's notify_on
+	}
+
+	for i, step := range steps {
+		transition := sm.Transition(step.status)
+		// bypass throttling so only the fan-out is under test
+		clearThrottle(sm)
+
+		if action := sm.ChannelAction(config.ChannelTelegram, telegramOn, transition, step.status, service); action.Action != step.wantTelegram {
+			t.Errorf("step %d: expected Telegram action %v, got %v", i+1, step.wantTelegram, action.Action)
+		}
+		if action := sm.ChannelAction(config.ChannelDiscord, discordOn, transition, step.status, service); action.Action != step.wantDiscord {
+			t.Errorf("step %d: expected Discord action %v, got %v", i+1, step.wantDiscord, action.Action)
+		}
+	}
+}
+
+func TestChannelActionThrottlesPerChannel(t *testing.T) {
+	sm := NewStateManager()
+	service := config.Service{Name: "Shared", URL: testExampleURL, Interval: time.Minute}
+	down := checker.ServiceStatus{ID: testServiceID, Name: "Shared", URL: testExampleURL, IsUp: false}
+	notifyOn := []string{"down", "recovery"}
+
+	sm.Transition(checker.ServiceStatus{ID: testServiceID, Name: "Shared", URL: testExampleURL, IsUp: true})
+	transition := sm.Transition(down)
+
+	// Telegram notified about this service moments ago, Discord did not
+	sm.tracker(testServiceID).lastNotification[config.ChannelTelegram] = time.Now()
+
+	if action := sm.ChannelAction(config.ChannelTelegram, notifyOn, transition, down, service); action.Action != NoAction {
+		t.Errorf("Expected Telegram to be throttled, got %v", action.Action)
+	}
+	if action := sm.ChannelAction(config.ChannelDiscord, notifyOn, transition, down, service); action.Action != NotifyDown {
+		t.Errorf("Expected Discord to be notified despite Telegram throttle, got %v", action.Action)
+	}
+	if action := sm.ChannelAction(config.ChannelDiscord, notifyOn, transition, down, service); action.Action != NoAction {
+		t.Errorf("Expected Discord to be throttled after its own notification, got %v", action.Action)
+	}
+}
+
+func TestTransitionRecoveryDowntime(t *testing.T) {
+	sm := NewStateManager()
+	up := checker.ServiceStatus{ID: testServiceID, Name: "Shared", URL: testExampleURL, IsUp: true}
+	down := checker.ServiceStatus{ID: testServiceID, Name: "Shared", URL: testExampleURL, IsUp: false}
+
+	sm.Transition(up)
+	sm.Transition(down)
+	time.Sleep(50 * time.Millisecond)
+
+	transition := sm.Transition(up)
+	if transition.Action != NotifyRecovery {
+		t.Fatalf("Expected NotifyRecovery, got %v", transition.Action)
+	}
+	if transition.Downtime < 50*time.Millisecond || transition.Downtime > time.Second {
+		t.Errorf("Expected downtime of about 50ms, got %v", transition.Downtime)
+	}
+}
+
+func TestProcessNotificationsDiscord(t *testing.T) {
+	var titles []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload notifier.DiscordWebhookPayload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err == nil && len(payload.Embeds) > 0 {
+			titles = append(titles, payload.Embeds[0].Title)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{Notifications: config.NotificationConfig{
+		Discord: config.DiscordConfig{Enabled: true, WebhookURL: server.URL, NotifyOn: []string{"down", "recovery"}},
+	}}
+	channels := newNotificationChannels(cfg.Notifications)
+	service := config.Service{Name: "Shared", URL: testExampleURL, Interval: time.Millisecond}
+	sm := NewStateManager()
+
+	for _, isUp := range []bool{true, false, true} {
+		processNotifications(channels, sm, checker.ServiceStatus{ID: testServiceID, Name: "Shared", URL: testExampleURL, IsUp: isUp}, service)
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if len(titles) != 2 || !strings.Contains(titles[0], "DOWN") || !strings.Contains(titles[1], "RECOVERED") {
+		t.Errorf("Expected DOWN and RECOVERED embeds, got %v", titles)
+	}
+}
+
+func TestStateManagerConcurrentServices(t *testing.T) {
+	sm := NewStateManager()
+	channels := newNotificationChannels(config.NotificationConfig{})
+	const services = 300
+	const rounds = 6
+
+	var mu sync.Mutex
+	downAlerts := make(map[string]int)
+
+	// Every round checks all services through a worker pool, as run does,
+	// alternating between UP and DOWN results
+	for round := 0; round < rounds; round++ {
+		jobs := make(chan int)
+		var wg sync.WaitGroup
+		for worker := 0; worker < 8; worker++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := range jobs {
+					url := fmt.Sprintf("https://service-%d.example.com", i)
+					service := config.Service{Name: url, URL: url, FailureThreshold: 1, SuccessThreshold: 1}
+					status := sm.ApplyThresholds(checker.ServiceStatus{ID: url, Name: url, URL: url, IsUp: round%2 == 0}, service)
+					sm.ProcessCertExpiry(status, service)
+					transition := sm.Transition(status)
+					processNotifications(channels, sm, checker.ServiceStatus{ID: url + "-other", Name: url, URL: url, IsUp: true}, service)
+					if sm.ChannelAction(config.ChannelDiscord, []string{"down"}, transition, status, service).Action == NotifyDown {
+						mu.Lock()
+						downAlerts[url]++
+						mu.Unlock()
+					}
+				}
+			}()
+		}
+		for i := 0; i < services; i++ {
+			jobs <- i
+		}
+		close(jobs)
+		wg.Wait()
+	}
+
+	if len(downAlerts) != services {
+		t.Fatalf("Expected DOWN alerts for all %d services, got %d", services, len(downAlerts))
+	}
+	for url, count := range downAlerts {
+		if count != rounds/2 {
+			t.Errorf("Expected %d DOWN alerts for %s, got %d", rounds/2, url, count)
+		}
+	}
+}
+
+func TestStateKeyedByServiceID(t *testing.T) {
+	sm := NewStateManager()
+	public := config.Service{ID: "api-public", Name: "API", URL: testExampleURL}
+	admin := config.Service{ID: "api-admin", Name: "API", URL: testExampleURL}
+
+	// Two services sharing a URL keep separate state
+	sm.ApplyThresholds(checker.ServiceStatus{ID: public.ID, URL: testExampleURL, IsUp: true}, public)
+	sm.ApplyThresholds(checker.ServiceStatus{ID: admin.ID, URL: testExampleURL, IsUp: false}, admin)
+	sm.Transition(checker.ServiceStatus{ID: public.ID, URL: testExampleURL, State: checker.StateUp})
+	sm.Transition(checker.ServiceStatus{ID: admin.ID, URL: testExampleURL, State: checker.StateDown})
+
+	if state := sm.Snapshot(public.ID).State; state != checker.StateUp {
+		t.Errorf("Expected api-public to be UP, got %s", state)
+	}
+	if state := sm.Snapshot(admin.ID).State; state != checker.StateDown {
+		t.Errorf("Expected api-admin to be DOWN, got %s", state)
+	}
+
+	// Changing the URL keeps the state of the same ID
+	moved := checker.ServiceStatus{ID: admin.ID, URL: "https://admin.example.com", IsUp: true, State: checker.StateUp}
+	if transition := sm.Transition(moved); transition.Action != NotifyRecovery {
+		t.Errorf("Expected recovery for api-admin at its new URL, got %v", transition.Action)
+	}
+}
+
+func TestResolveServiceID(t *testing.T) {
+	services := []config.Service{
+		{ID: "api", Name: "Public API"},
+		{Name: "Web Shop"},
+	}
+
+	tests := map[string]string{
+		"Public API": "api",
+		"api":        "api",
+		"Web Shop":   "web-shop",
+		"web-shop":   "web-shop",
+		"removed":    "removed",
+	}
+	for arg, want := range tests {
+		if got := resolveServiceID(services, arg); got != want {
+			t.Errorf("resolveServiceID(%q) = %q, want %q", arg, got, want)
+		}
+	}
+}
+
+func TestStateManagerRestore(t *testing.T) {
+	service := config.Service{Name: "Restored", URL: testExampleURL, Interval: time.Minute}
+	up := checker.ServiceStatus{ID: testServiceID, Name: "Restored", URL: testExampleURL, IsUp: true}
+	down := checker.ServiceStatus{ID: testServiceID, Name: "Restored", URL: testExampleURL, IsUp: false}
+	downSince := time.Now().Add(-2 * time.Hour)
+
+	// A service that was DOWN before the restart recovers with its full downtime
+	sm := NewStateManager()
+	sm.Restore([]storage.ServiceState{{ServiceID: testServiceID, State: checker.StateDown, DownSince: downSince}})
+	transition := sm.Transition(sm.ApplyThresholds(up, service))
+	if transition.Action != NotifyRecovery {
+		t.Fatalf("Expected NotifyRecovery after restore, got %v", transition.Action)
+	}
+	if transition.Downtime < 2*time.Hour || transition.Downtime > 2*time.Hour+time.Minute {
+		t.Errorf("Expected downtime of about 2h, got %v", transition.Downtime)
+	}
+
+	// A service that was UP alerts on its first failed check
+	sm = NewStateManager()
+	sm.Restore([]storage.ServiceState{{ServiceID: testServiceID, State: checker.StateUp}})
+	if transition := sm.Transition(sm.ApplyThresholds(down, service)); transition.Action != NotifyDown {
+		t.Errorf("Expected NotifyDown after restore, got %v", transition.Action)
+	}
+
+	// Restored notification times keep throttling
+	snapshot := sm.Snapshot(testServiceID)
+	if snapshot.State != checker.StateDown || snapshot.DownSince.IsZero() {
+		t.Fatalf("Expected DOWN snapshot with down-since, got %+v", snapshot)
+	}
+	snapshot.State = checker.StateUp
+	snapshot.LastNotification = map[string]time.Time{config.ChannelTelegram: time.Now()}
+	sm = NewStateManager()
+	sm.Restore([]storage.ServiceState{snapshot})
+	transition = sm.Transition(sm.ApplyThresholds(down, service))
+	if action := sm.ChannelAction(config.ChannelTelegram, []string{"down"}, transition, down, service); action.Action != NoAction {
+		t.Errorf("Expected restored notification time to throttle Telegram, got %v", action.Action)
+	}
+	if action := sm.ChannelAction(config.ChannelDiscord, []string{"down"}, transition, down, service); action.Action != NotifyDown {
+		t.Errorf("Expected Discord to be notified, got %v", action.Action)
+	}
+}
+
+func TestReminderAction(t *testing.T) {
+	sm := NewStateManager()
+	service := config.Service{Name: "Outage", URL: testExampleURL, Interval: time.Minute}
+	up := checker.ServiceStatus{ID: testServiceID, Name: "Outage", URL: testExampleURL, IsUp: true}
+	down := checker.ServiceStatus{ID: testServiceID, Name: "Outage", URL: testExampleURL, IsUp: false}
+	reminders := config.Reminders{RepeatInterval: time.Hour, MaxReminders: 2}
+	notifyOn := []string{"down", "recovery"}
+
+	// rewind moves the last notification of a channel back in time
+	rewind := func(channel string, d time.Duration) {
+		tracker := sm.tracker(testServiceID)
+		tracker.lastNotification[channel] = tracker.lastNotification[channel].Add(-d)
+		tracker.downSince = tracker.downSince.Add(-d)
+	}
+
+	sm.Transition(up)
+	if action := sm.ReminderAction(config.ChannelTelegram, reminders, up); action.Action != NoAction {
+		t.Errorf("Expected no reminder while UP, got %v", action.Action)
+	}
+
+	transition := sm.Transition(down)
+	if action := sm.ChannelAction(config.ChannelTelegram, notifyOn, transition, down, service); action.Action != NotifyDown {
+		t.Fatalf("Expected NotifyDown, got %v", action.Action)
+	}
+	if action := sm.ReminderAction(config.ChannelTelegram, reminders, down); action.Action != NoAction {
+		t.Errorf("Expected no reminder right after the DOWN alert, got %v", action.Action)
+	}
+	// Discord never sent the DOWN alert, so it gets no reminders either
+	if action := sm.ReminderAction(config.ChannelDiscord, reminders, down); action.Action != NoAction {
+		t.Errorf("Expected no reminder on a channel without DOWN alert, got %v", action.Action)
+	}
+
+	for i := 1; i <= 2; i++ {
+		rewind(config.ChannelTelegram, time.Hour)
+		action := sm.ReminderAction(config.ChannelTelegram, reminders, down)
+		if action.Action != NotifyStillDown {
+			t.Fatalf("reminder %d: expected NotifyStillDown, got %v", i, action.Action)
+		}
+		if action.Downtime < time.Duration(i)*time.Hour {
+			t.Errorf("reminder %d: expected downtime of at least %dh, got %v", i, i, action.Downtime)
+		}
+	}
+
+	// The cap is reached
+	rewind(config.ChannelTelegram, time.Hour)
+	if action := sm.ReminderAction(config.ChannelTelegram, reminders, down); action.Action != NoAction {
+		t.Errorf("Expected max_reminders to stop reminders, got %v", action.Action)
+	}
+
+	// A new outage re-arms reminders
+	sm.Transition(up)
+	transition = sm.Transition(down)
+	clearThrottle(sm)
+	sm.ChannelAction(config.ChannelTelegram, notifyOn, transition, down, service)
+	rewind(config.ChannelTelegram, time.Hour)
+	if action := sm.ReminderAction(config.ChannelTelegram, reminders, down); action.Action != NotifyStillDown {
+		t.Errorf("Expected reminders to restart with a new outage, got %v", action.Action)
+	}
+}
+
+func TestProcessNotificationsDiscordReminders(t *testing.T) {
+	var titles []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload notifier.DiscordWebhookPayload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err == nil && len(payload.Embeds) > 0 {
+			titles = append(titles, payload.Embeds[0].Title)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{Notifications: config.NotificationConfig{
+		Discord: config.DiscordConfig{
+			Enabled:    true,
+			WebhookURL: server.URL,
+			NotifyOn:   []string{"down"},
+			Reminders:  config.Reminders{RepeatInterval: 20 * time.Millisecond, MaxReminders: 2},
+		},
+	}}
+	channels := newNotificationChannels(cfg.Notifications)
+	service := config.Service{Name: "Outage", URL: testExampleURL, Interval: time.Millisecond}
+	sm := NewStateManager()
+
+	processNotifications(channels, sm, checker.ServiceStatus{ID: testServiceID, Name: "Outage", URL: testExampleURL, IsUp: true}, service)
+	for i := 0; i < 5; i++ {
+		processNotifications(channels, sm, checker.ServiceStatus{ID: testServiceID, Name: "Outage", URL: testExampleURL, IsUp: false}, service)
+		time.Sleep(30 * time.Millisecond)
+	}
+
+	want := []string{"🔴 Service DOWN", "🔴 Service STILL DOWN", "🔴 Service STILL DOWN"}
+	if strings.Join(titles, "|") != strings.Join(want, "|") {
+		t.Errorf("Expected %v, got %v", want, titles)
+	}
+}
+
+// fakeClock is a manually advanced clock for StateManager
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func (c *fakeClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+func newTestClock(sm *StateManager) *fakeClock {
+	clock := &fakeClock{now: time.Date(2025, 10, 11, 22, 0, 0, 0, time.UTC)}
+	sm.now = clock.Now
+	return clock
+}
+
+func testEscalationPolicy() config.EscalationPolicy {
+	return config.EscalationPolicy{
+		Name: "on-call",
+		Steps: []config.EscalationStep{
+			{Channels: []string{config.ChannelDiscord}},
+			{After: 15 * time.Minute, Channels: []string{config.ChannelTelegram}},
+			{After: time.Hour, Channels: []string{config.ChannelTelegram}, ChatID: "managers"},
+		},
+	}
+}
+
+func TestEscalationActions(t *testing.T) {
+	sm := NewStateManager()
+	clock := newTestClock(sm)
+	policy := testEscalationPolicy()
+	up := checker.ServiceStatus{ID: testServiceID, Name: "Escalated", URL: testExampleURL, IsUp: true}
+	down := checker.ServiceStatus{ID: testServiceID, Name: "Escalated", URL: testExampleURL, IsUp: false}
+
+	// check advances the clock, runs one check and returns the escalation alerts
+	check := func(d time.Duration, status checker.ServiceStatus) []EscalationAlert {
+		clock.Advance(d)
+		return sm.EscalationActions(policy, sm.Transition(status), status)
+	}
+
+	if alerts := check(0, up); len(alerts) != 0 {
+		t.Fatalf("Expected no alerts while UP, got %v", alerts)
+	}
+
+	alerts := check(time.Minute, down)
+	if len(alerts) != 1 || alerts[0].Action != NotifyDown || alerts[0].Step.Channels[0] != config.ChannelDiscord {
+		t.Fatalf("Expected an immediate DOWN alert to discord, got %v", alerts)
+	}
+	if alerts := check(10*time.Minute, down); len(alerts) != 0 {
+		t.Errorf("Expected no escalation after 10m, got %v", alerts)
+	}
+
+	alerts = check(5*time.Minute, down)
+	if len(alerts) != 1 || alerts[0].Action != NotifyStillDown || alerts[0].Step.Channels[0] != config.ChannelTelegram {
+		t.Fatalf("Expected escalation to telegram after 15m, got %v", alerts)
+	}
+	if alerts[0].Downtime != 15*time.Minute {
+		t.Errorf("Expected downtime 15m, got %v", alerts[0].Downtime)
+	}
+
+	alerts = check(time.Hour, down)
+	if len(alerts) != 1 || alerts[0].Step.ChatID != "managers" {
+		t.Fatalf("Expected escalation to the managers chat after 1h, got %v", alerts)
+	}
+	if alerts := check(time.Hour, down); len(alerts) != 0 {
+		t.Errorf("Expected no alerts once every step was sent, got %v", alerts)
+	}
+
+	alerts = check(time.Minute, up)
+	if len(alerts) != 3 {
+		t.Fatalf("Expected recovery to reach all 3 steps, got %v", alerts)
+	}
+	for _, alert := range alerts {
+		if alert.Action != NotifyRecovery {
+			t.Errorf("Expected NotifyRecovery, got %v", alert.Action)
+		}
+	}
+
+	// A new outage starts over, and a long gap between checks sends every
+	// step that became due at once
+	if alerts := check(time.Minute, down); len(alerts) != 1 {
+		t.Fatalf("Expected a new outage to start at the first step, got %v", alerts)
+	}
+	if alerts := check(2*time.Hour, down); len(alerts) != 2 {
+		t.Errorf("Expected both remaining steps after 2h, got %v", alerts)
+	}
+	if got := sm.Snapshot(testServiceID).EscalationStep; got != 3 {
+		t.Errorf("Expected snapshot at escalation step 3, got %d", got)
+	}
+}
+
+func TestProcessNotificationsEscalation(t *testing.T) {
+	// recorder returns a Discord webhook that records embed titles
+	recorder := func(titles *[]string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var payload notifier.DiscordWebhookPayload
+			if err := json.NewDecoder(r.Body).Decode(&payload); err == nil && len(payload.Embeds) > 0 {
+				*titles = append(*titles, payload.Embeds[0].Title)
+			}
+			w.WriteHeader(http.StatusNoContent)
+		}))
+	}
+	var teamTitles, managerTitles []string
+	team := recorder(&teamTitles)
+	defer team.Close()
+	managers := recorder(&managerTitles)
+	defer managers.Close()
+
+	cfg := &config.Config{Notifications: config.NotificationConfig{
+		Discord: config.DiscordConfig{Enabled: true, WebhookURL: team.URL, NotifyOn: []string{"down", "recovery"}},
+		EscalationPolicies: []config.EscalationPolicy{{
+			Name: "on-call",
+			Steps: []config.EscalationStep{
+				{Channels: []string{config.ChannelDiscord}},
+				{After: 15 * time.Minute, Channels: []string{config.ChannelDiscord}, WebhookURL: managers.URL},
+			},
+		}},
+	}}
+	channels := newNotificationChannels(cfg.Notifications)
+	service := config.Service{Name: "Escalated", URL: testExampleURL, Interval: time.Minute, Escalation: "on-call"}
+	sm := NewStateManager()
+	clock := newTestClock(sm)
+
+	for _, check := range []struct {
+		after time.Duration
+		isUp  bool
+	}{{0, true}, {time.Minute, false}, {10 * time.Minute, false}, {10 * time.Minute, false}, {10 * time.Minute, true}} {
+		clock.Advance(check.after)
+		processNotifications(channels, sm, checker.ServiceStatus{ID: testServiceID, Name: "Escalated", URL: testExampleURL, IsUp: check.isUp}, service)
+	}
+
+	wantTeam := []string{"🔴 Service DOWN", "🟢 Service RECOVERED"}
+	if strings.Join(teamTitles, "|") != strings.Join(wantTeam, "|") {
+		t.Errorf("Expected team alerts %v, got %v", wantTeam, teamTitles)
+	}
+	wantManagers := []string{"🔴 Service STILL DOWN", "🟢 Service RECOVERED"}
+	if strings.Join(managerTitles, "|") != strings.Join(wantManagers, "|") {
+		t.Errorf("Expected manager alerts %v, got %v", wantManagers, managerTitles)
+	}
+}
+
+func TestProcessNotificationsSlackEscalation(t *testing.T) {
+	// recorder returns a Slack webhook that records message texts
+	recorder := func(texts *[]string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var message notifier.SlackMessage
+			if err := json.NewDecoder(r.Body).Decode(&message); err == nil {
+				*texts = append(*texts, message.Text)
+			}
+			w.Write([]byte("ok"))
+		}))
+	}
+	var teamTexts, managerTexts []string
+	team := recorder(&teamTexts)
+	defer team.Close()
+	managers := recorder(&managerTexts)
+	defer managers.Close()
+
+	cfg := &config.Config{Notifications: config.NotificationConfig{
+		Slack: config.SlackConfig{Enabled: true, WebhookURL: team.URL, NotifyOn: []string{"down", "recovery"}},
+		EscalationPolicies: []config.EscalationPolicy{{
+			Name: "on-call",
+			Steps: []config.EscalationStep{
+				{Channels: []string{config.ChannelSlack}},
+				{After: 15 * time.Minute, Channels: []string{config.ChannelSlack}, WebhookURL: managers.URL},
+			},
+		}},
+	}}
+	channels := newNotificationChannels(cfg.Notifications)
+	service := config.Service{Name: "Escalated", URL: testExampleURL, Interval: time.Minute, Escalation: "on-call"}
+	sm := NewStateManager()
+	clock := newTestClock(sm)
+
+	for _, check := range []struct {
+		after time.Duration
+		isUp  bool
+	}{{0, true}, {time.Minute, false}, {20 * time.Minute, false}, {10 * time.Minute, true}} {
+		clock.Advance(check.after)
+		processNotifications(channels, sm, checker.ServiceStatus{ID: testServiceID, Name: "Escalated", URL: testExampleURL, IsUp: check.isUp}, service)
+	}
+
+	wantTeam := []string{"🔴 Service DOWN: Escalated", "🟢 Service RECOVERED: Escalated"}
+	if strings.Join(teamTexts, "|") != strings.Join(wantTeam, "|") {
+		t.Errorf("Expected team alerts %v, got %v", wantTeam, teamTexts)
+	}
+	wantManagers := []string{"🔴 Service STILL DOWN: Escalated", "🟢 Service RECOVERED: Escalated"}
+	if strings.Join(managerTexts, "|") != strings.Join(wantManagers, "|") {
+		t.Errorf("Expected manager alerts %v, got %v", wantManagers, managerTexts)
+	}
+}
+
+func TestFormatIncidentRow(t *testing.T) {
+	started := time.Date(2025, 10, 11, 22, 0, 0, 0, time.UTC)
+
+	closed := storage.Incident{
+		ID:          7,
+		ServiceName: "Payments API",
+		StartedAt:   started,
+		EndedAt:     started.Add(3*time.Hour + 12*time.Minute),
+		Duration:    3*time.Hour + 12*time.Minute,
+		FirstError:  "connection refused",
+		Checks:      192,
+	}
+	want := "7     | Payments API         | 2025-10-11 22:00:00 | 2025-10-12 01:12:00 | 3h12m0s      | 192    | connection refused"
+	if got := formatIncidentRow(closed, started.Add(24*time.Hour)); got != want {
+		t.Errorf("Unexpected row for a closed incident:\nExpected: %s\nGot:      %s", want, got)
+	}
+
+	open := storage.Incident{ID: 8, ServiceName: "Payments API", StartedAt: started, Checks: 5}
+	row := formatIncidentRow(open, started.Add(5*time.Minute))
+	if !strings.Contains(row, "| ongoing             | 5m0s ") || !strings.HasSuffix(row, "| -") {
+		t.Errorf("Expected an ongoing incident lasting 5m0s without error, got %s", row)
+	}
+}
+
+func TestParseReportRange(t *testing.T) {
+	now := time.Date(2025, 10, 11, 22, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		window   string
+		from, to string
+		wantFrom time.Time
+		wantTo   time.Time
+		wantErr  bool
+	}{
+		{name: "hours", window: "24h", wantFrom: now.Add(-24 * time.Hour), wantTo: now},
+		{name: "days", window: "7d", wantFrom: now.AddDate(0, 0, -7), wantTo: now},
+		{name: "dates", window: "24h", from: "2025-09-01", to: "2025-10-01",
+			wantFrom: time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC), wantTo: time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "from until now", from: "2025-10-11T12:00:00Z", wantFrom: now.Add(-10 * time.Hour), wantTo: now},
+		{name: "invalid window", window: "a week", wantErr: true},
+		{name: "negative window", window: "-7d", wantErr: true},
+		{name: "from after to", from: "2025-10-01", to: "2025-09-01", wantErr: true},
+		{name: "invalid date", from: "yesterday", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to, err := parseReportRange(tt.window, tt.from, tt.to, now)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("Expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !from.Equal(tt.wantFrom) || !to.Equal(tt.wantTo) {
+				t.Errorf("Expected %v - %v, got %v - %v", tt.wantFrom, tt.wantTo, from, to)
+			}
+		})
+	}
+}
+
+func TestWriteReport(t *testing.T) {
+	to := time.Date(2025, 10, 11, 0, 0, 0, 0, time.UTC)
+	from := to.Add(-24 * time.Hour)
+	reports := []storage.ServiceReport{
+		{
+			ServiceID: "api", ServiceName: "API", Checks: 1440, UpChecks: 1430,
+			Incidents: 2, Resolved: 2, RepairTime: 20 * time.Minute,
+			Samples: 1430, P50: 120 * time.Millisecond, P95: 480 * time.Millisecond, P99: 1200 * time.Millisecond,
+		},
+		{ServiceID: "web", ServiceName: "Web", Checks: 1440, UpChecks: 1440, Samples: 1440, P99: time.Millisecond},
+		{ServiceID: "worker", ServiceName: "Worker", Checks: 60},
+	}
+
+	var out strings.Builder
+	if err := writeReport(&out, "csv", reports, from, to); err != nil {
+		t.Fatalf("Failed to write CSV: %v", err)
+	}
+	wantCSV := `id,name,checks,uptime_percent,incidents,mttr_seconds,mtbf_seconds,p50_ms,p95_ms,p99_ms
+api,API,1440,99.306,2,600,42900,120,480,1200
+web,Web,1440,100.000,0,,,0,0,1
+worker,Worker,60,0.000,0,,,,,
+`
+	if out.String() != wantCSV {
+		t.Errorf("Unexpected CSV:\n%s", out.String())
+	}
+
+	out.Reset()
+	if err := writeReport(&out, "json", reports, from, to); err != nil {
+		t.Fatalf("Failed to write JSON: %v", err)
+	}
+	var decoded reportJSON
+	if err := json.Unmarshal([]byte(out.String()), &decoded); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	if len(decoded.Services) != 3 || *decoded.Services[0].MTTRSeconds != 600 || decoded.Services[1].MTBFSeconds != nil {
+		t.Errorf("Unexpected JSON report: %s", out.String())
+	}
+	if web := decoded.Services[1]; web.P50Ms == nil || *web.P50Ms != 0 || *web.P99Ms != 1 {
+		t.Errorf("Expected p50 of 0ms and p99 of 1ms for web, got %s", out.String())
+	}
+	if worker := decoded.Services[2]; worker.P50Ms != nil || worker.P95Ms != nil || worker.P99Ms != nil {
+		t.Errorf("Expected null percentiles without samples, got %s", out.String())
+	}
+
+	out.Reset()
+	if err := writeReport(&out, "table", reports, from, to); err != nil {
+		t.Fatalf("Failed to write table: %v", err)
+	}
+	if !strings.Contains(out.String(), "API                  | 99.306%  | 2         | 10m0s      | 11h55m0s   | 120ms    | 480ms    | 1s") {
+		t.Errorf("Unexpected table:\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), "| 0ms      | 0ms      | 1ms\n") || !strings.Contains(out.String(), "| -        | -        | -\n") {
+		t.Errorf("Expected 0ms with samples and - without, got:\n%s", out.String())
+	}
+
+	if err := writeReport(&out, "xml", reports, from, to); err == nil {
+		t.Error("Expected error for an unknown format")
+	}
+}
+
+func TestBuildHistoryQuery(t *testing.T) {
+	defer func() {
+		historySince, historyUntil, historyStatus, historyMinLatency = "", "", "", 0
+	}()
+
+	now := time.Date(2025, 10, 11, 22, 0, 0, 0, time.UTC)
+	services := []config.Service{{ID: "api", Name: "Public API"}}
+
+	historySince, historyUntil, historyStatus, historyMinLatency = "7d", "2025-10-11", "down", time.Second
+	query, err := buildHistoryQuery(services, []string{"Public API", "legacy"}, now)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if strings.Join(query.ServiceIDs, ",") != "api,legacy" {
+		t.Errorf("Expected service ids api and legacy, got %v", query.ServiceIDs)
+	}
+	if !query.Since.Equal(now.AddDate(0, 0, -7)) || !query.Until.Equal(time.Date(2025, 10, 11, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected range %v - %v", query.Since, query.Until)
+	}
+	if query.Status != checker.StateDown || query.MinLatency != time.Second {
+		t.Errorf("Unexpected filters: %+v", query)
+	}
+
+	historySince, historyUntil, historyStatus = "", "", "sideways"
+	if _, err := buildHistoryQuery(services, nil, now); err == nil {
+		t.Error("Expected error for an invalid status")
+	}
+	historySince, historyStatus = "last tuesday", ""
+	if _, err := buildHistoryQuery(services, nil, now); err == nil {
+		t.Error("Expected error for an invalid --since")
+	}
+}
+
+func TestWriteHistory(t *testing.T) {
+	checkedAt := time.Date(2025, 10, 11, 22, 0, 0, 0, time.UTC)
+	records := []storage.CheckRecord{
+		{ServiceID: "api", ServiceName: "API", ServiceURL: testExampleURL, IsUp: false, ResponseTimeMs: 5000, ErrorMessage: "timeout, retrying", CheckedAt: checkedAt, State: checker.StateDown, Attempts: 3},
+		{ServiceID: "web", ServiceName: "Web", ServiceURL: testExampleURL, IsUp: true, StatusCode: 200, ResponseTimeMs: 42, CheckedAt: checkedAt, Attempts: 1},
+	}
+
+	var out strings.Builder
+	if err := writeHistory(&out, "csv", nil, records); err != nil {
+		t.Fatalf("Failed to write CSV: %v", err)
+	}
+	wantCSV := `checked_at,service_id,service_name,url,status,state,response_time_ms,status_code,error,attempts
+2025-10-11T22:00:00Z,api,API,https://example.com,DOWN,DOWN,5000,0,"timeout, retrying",3
+2025-10-11T22:00:00Z,web,Web,https://example.com,UP,,42,200,,1
+`
+	if out.String() != wantCSV {
+		t.Errorf("Unexpected CSV:\n%s", out.String())
+	}
+
+	out.Reset()
+	if err := writeHistory(&out, "json", nil, records); err != nil {
+		t.Fatalf("Failed to write JSON: %v", err)
+	}
+	var decoded []checkRecordJSON
+	if err := json.Unmarshal([]byte(out.String()), &decoded); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	if len(decoded) != 2 || decoded[0].Status != checker.StateDown || decoded[1].StatusCode != 200 {
+		t.Errorf("Unexpected JSON: %s", out.String())
+	}
+
+	// Several services get a service column, a single one is named in the title
+	out.Reset()
+	writeHistory(&out, "table", nil, records)
+	if !strings.Contains(out.String(), "| Web                  | UP ") {
+		t.Errorf("Expected a service column:\n%s", out.String())
+	}
+	out.Reset()
+	writeHistory(&out, "table", []string{"api"}, records[:1])
+	if !strings.Contains(out.String(), "Check History for 'api'") || strings.Contains(out.String(), "| API ") {
+		t.Errorf("Expected the service in the title only:\n%s", out.String())
+	}
+}
+
+func testOnceStatuses() []checker.ServiceStatus {
+	return []checker.ServiceStatus{
+		{ID: "api", Name: "API", URL: "https://api.example.com", IsUp: true, StatusCode: 200, ResponseTime: 120 * time.Millisecond},
+		{ID: "web", Name: "Web #2", URL: "https://web.example.com", IsUp: false, StatusCode: 503, ResponseTime: 40 * time.Millisecond},
+		{ID: "db", Name: "DB", URL: "tcp://db:5432", IsUp: false, Error: errors.New("connection refused"),
+			Attempts: []checker.Attempt{{}, {}, {}}},
+	}
+}
+
+func TestWriteCheckResultsJSON(t *testing.T) {
+	started := time.Date(2025, 10, 11, 22, 0, 0, 0, time.UTC)
+	var out strings.Builder
+	if err := writeCheckResults(&out, outputJSON, testOnceStatuses(), started, 1500*time.Millisecond); err != nil {
+		t.Fatalf("Failed to write JSON: %v", err)
+	}
+
+	var decoded checkResultsJSON
+	if err := json.Unmarshal([]byte(out.String()), &decoded); err != nil {
+		t.Fatalf("Invalid JSON: %v\n%s", err, out.String())
+	}
+	if decoded.AllUp || decoded.DurationMs != 1500 || len(decoded.Services) != 3 {
+		t.Fatalf("Unexpected summary: %+v", decoded)
+	}
+	db := decoded.Services[2]
+	if db.Status != checker.StateDown || db.Error != "connection refused" || db.Attempts != 3 {
+		t.Errorf("Unexpected DB result: %+v", db)
+	}
+}
+
+func TestWriteCheckResultsJUnit(t *testing.T) {
+	var out strings.Builder
+	if err := writeCheckResults(&out, outputJUnit, testOnceStatuses(), time.Now(), time.Second); err != nil {
+		t.Fatalf("Failed to write JUnit: %v", err)
+	}
+
+	var decoded junitTestSuites
+	if err := xml.Unmarshal([]byte(out.String()), &decoded); err != nil {
+		t.Fatalf("Invalid XML: %v\n%s", err, out.String())
+	}
+	if decoded.Tests != 3 || decoded.Failures != 2 || len(decoded.Suites) != 1 {
+		t.Fatalf("Expected 3 tests with 2 failures, got %+v", decoded)
+	}
+
+	cases := decoded.Suites[0].Cases
+	if cases[0].Failure != nil || cases[0].Time != "0.120" {
+		t.Errorf("Expected a passing API testcase taking 0.120s, got %+v", cases[0])
+	}
+	if cases[1].Failure == nil || cases[1].Failure.Message != "unexpected status code 503" || !strings.Contains(cases[1].Failure.Details, "status code: 503") {
+		t.Errorf("Expected the Web failure to carry the status code, got %+v", cases[1].Failure)
+	}
+	if cases[2].Failure == nil || cases[2].Failure.Message != "connection refused" || !strings.Contains(cases[2].Failure.Details, "failed after 3 attempts") {
+		t.Errorf("Expected the DB failure to carry the error, got %+v", cases[2].Failure)
+	}
+}
+
+func TestWriteCheckResultsTAP(t *testing.T) {
+	var out strings.Builder
+	if err := writeCheckResults(&out, outputTAP, testOnceStatuses(), time.Now(), time.Second); err != nil {
+		t.Fatalf("Failed to write TAP: %v", err)
+	}
+
+	want := `TAP version 13
+1..3
+ok 1 - API (UP, 120 ms, HTTP 200)
+not ok 2 - Web \#2 (DOWN, 40 ms, HTTP 503)
+  ---
+  message: "unexpected status code 503"
+  url: "https://web.example.com"
+  status_code: 503
+  response_time_ms: 40
+  ...
+not ok 3 - DB (DOWN, 0 ms)
+  ---
+  message: "connection refused"
+  url: "tcp://db:5432"
+  response_time_ms: 0
+  attempts: 3
+  ...
+`
+	if out.String() != want {
+		t.Errorf("Unexpected TAP:\n%s", out.String())
+	}
+
+	if err := writeCheckResults(&out, "yaml", nil, time.Now(), 0); err == nil {
+		t.Error("Expected error for an unknown format")
+	}
+}
+
+// sleepChecker takes as long as the duration in the service host to answer
+type sleepChecker struct{}
+
+var registerSleepChecker sync.Once
+
+func (sleepChecker) Check(service config.Service) checker.ServiceStatus {
+	d, _ := time.ParseDuration(service.Host)
+	time.Sleep(d)
+	return checker.ServiceStatus{Name: service.Name, URL: service.Host, IsUp: !strings.HasPrefix(service.Name, "down")}
+}
+
+func TestRunChecksConcurrentOrdered(t *testing.T) {
+	registerSleepChecker.Do(func() { checker.Register("cmd-test-sleep", sleepChecker{}) })
+	t.Setenv(envWorkerCount, "4")
+
+	// Later services finish first, output must still follow the configuration
+	var services []config.Service
+	for i, delay := range []string{"400ms", "300ms", "200ms", "100ms"} {
+		name := fmt.Sprintf("svc-%d", i)
+		if i == 2 {
+			name = "down-" + name
+		}
+		services = append(services, config.Service{Name: name, Type: "cmd-test-sleep", Host: delay})
+	}
+	cfg := &config.Config{Services: services}
+
+	var reported []string
+	start := time.Now()
+	statuses := runChecks(cfg, NewStateManager(), nil, func(status checker.ServiceStatus) {
+		reported = append(reported, status.Name)
+	})
+	elapsed := time.Since(start)
+
+	if elapsed >= 900*time.Millisecond {
+		t.Errorf("Expected checks to run concurrently, took %v", elapsed)
+	}
+	want := "svc-0,svc-1,down-svc-2,svc-3"
+	if strings.Join(reported, ",") != want {
+		t.Errorf("Expected report order %s, got %v", want, reported)
+	}
+	for i, status := range statuses {
+		if status.Name != services[i].Name {
+			t.Errorf("Expected status %d to be %s, got %s", i, services[i].Name, status.Name)
+		}
+	}
+	if allUp(statuses) {
+		t.Error("Expected a DOWN service to fail the run")
+	}
+
+	if statuses := runChecks(&config.Config{}, NewStateManager(), nil, nil); len(statuses) != 0 {
+		t.Errorf("Expected no statuses without services, got %v", statuses)
+	}
+}
+
+func TestRunChecksConcurrentStorage(t *testing.T) {
+	registerSleepChecker.Do(func() { checker.Register("cmd-test-sleep", sleepChecker{}) })
+	t.Setenv(envWorkerCount, "8")
+
+	store, err := storage.NewSQLiteStorage(filepath.Join(t.TempDir(), "once.db"))
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	defer store.Close()
+
+	// All checks finish at once, so every worker saves at the same time
+	var services []config.Service
+	for i := 0; i < 8; i++ {
+		name := fmt.Sprintf("svc-%d", i)
+		if i%2 == 0 {
+			name = "down-" + name
+		}
+		services = append(services, config.Service{Name: name, Type: "cmd-test-sleep", Host: "0s"})
+	}
+	cfg := &config.Config{Services: services}
+
+	const runs = 5
+	sm := NewStateManager()
+	for run := 0; run < runs; run++ {
+		runChecks(cfg, sm, store, nil)
+	}
+
+	records, err := store.QueryHistory(storage.HistoryQuery{})
+	if err != nil {
+		t.Fatalf("Failed to query history: %v", err)
+	}
+	if len(records) != runs*len(services) {
+		t.Errorf("Expected %d saved checks, got %d", runs*len(services), len(records))
+	}
+
+	incidents, err := store.GetIncidents("", 100)
+	if err != nil {
+		t.Fatalf("Failed to get incidents: %v", err)
+	}
+	if len(incidents) != len(services)/2 {
+		t.Fatalf("Expected an incident for each DOWN service, got %d", len(incidents))
+	}
+	for _, incident := range incidents {
+		if !incident.Open() || incident.Checks != runs {
+			t.Errorf("Expected an open incident with %d checks, got %+v", runs, incident)
+		}
+	}
+
+	// once persists the state like run does
+	states, err := store.LoadStates()
+	if err != nil {
+		t.Fatalf("Failed to load states: %v", err)
+	}
+	if len(states) != len(services) {
+		t.Errorf("Expected a saved state for every service, got %d", len(states))
+	}
+}
@@ -21,10 +21,6 @@ const (
 	// timestamp format (Go reference time)
 	timestampFormat = "2006-01-02 15:04:05"
 
-	// allowed URL schemes
-	schemeHTTP  = "http"
-	schemeHTTPS = "https"
-
 	// exit codes
 	exitSuccess     = 0
 	exitError       = 1
@@ -54,14 +50,21 @@ const (
 	msgInvalidWorkerCountEnv = "Invalid worker count for %s: %q. Using default (%d).\n"
 
 	// error messages
-	errLoadingConfig          = "Error loading configuration: %v\n"
-	errInvalidConfigPath      = "invalid config path: %w"
-	errConfigNotFound         = "config file not found: %s\nCreate a %s file or use --%s flag"
-	errServiceNameReq         = "service #%d: name is required"
-	errServiceURLReq          = "service #%d (%s): URL is required"
-	errServiceURLInvalid      = "service #%d (%s): invalid URL format '%s'"
-	errServiceIntervalInvalid = "service #%d (%s): interval must be positive"
-	errServiceTimeoutInvalid  = "service #%d (%s): timeout must be positive"
+	errLoadingConfig           = "Error loading configuration: %v\n"
+	errInvalidConfigPath       = "invalid config path: %w"
+	errConfigNotFound          = "config file not found: %s\nCreate a %s file or use --%s flag"
+	errServiceNameReq          = "service #%d: name is required"
+	errServiceIntervalInvalid  = "service #%d (%s): interval must be positive"
+	errServiceTimeoutInvalid   = "service #%d (%s): timeout must be positive"
+	errServiceThresholdInvalid = "service #%d (%s): %s must be positive"
+	errServiceNotNegative      = "service #%d (%s): %s must not be negative"
+	errServiceRetriesTooLong   = "service #%d (%s): %d retries can take up to %v, longer than the interval of %v"
+	errServiceTypeInvalid      = "service #%d (%s): unsupported type '%s', expected one of %s"
+	errServiceInvalid          = "service #%d (%s): %v"
+	errServiceIDReq            = "service #%d (%s): id is required when the name has no letters or digits"
+	errServiceIDInvalid        = "service #%d (%s): invalid id '%s', use letters, digits, '.', '-' or '_'"
+	errServiceIDDuplicate      = "service #%d (%s): id '%s' is already used by service #%d"
+	errNotificationInvalid     = "notifications.%s: %v"
 
 	// command descriptions
 	descShort      = "A simple and effective monitoring system"
@@ -1,23 +1,36 @@
 package cmd
 
 import (
+	"encoding/csv"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/0xReLogic/SENTINEL/config"
 	"github.com/0xReLogic/SENTINEL/storage"
 	"github.com/spf13/cobra"
 )
 
-var historyLimit int
+var (
+	historyLimit      int
+	historySince      string
+	historyUntil      string
+	historyStatus     string
+	historyMinLatency time.Duration
+	historyFormat     string
+)
 
 var historyCmd = &cobra.Command{
-	Use:   "history [service-name]",
-	Short: "View check history for a service",
-	Long:  "Display historical check results for a specific service from the database",
-	Args:  cobra.ExactArgs(1),
+	Use:   "history [service-name-or-id...]",
+	Short: "View check history for services",
+	Long: `Display historical check results from the database, newest first. Services are matched by name or id;
+use the id for services no longer in the configuration. Without a service the checks of all services are shown.
+--since and --until take a date (2006-01-02), an RFC 3339 timestamp or a time ago such as 24h or 7d.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		serviceName := args[0]
-
 		cfg, err := loadConfig(configPath)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
@@ -29,6 +42,12 @@ var historyCmd = &cobra.Command{
 			os.Exit(exitConfigError)
 		}
 
+		query, err := buildHistoryQuery(cfg.Services, args, time.Now())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(exitError)
+		}
+
 		store, err := storage.NewSQLiteStorage(cfg.Storage.Path)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error opening storage: %v\n", err)
@@ -36,52 +55,222 @@ var historyCmd = &cobra.Command{
 		}
 		defer store.Close()
 
-		records, err := store.GetHistory(serviceName, historyLimit)
+		records, err := store.QueryHistory(query)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error retrieving history: %v\n", err)
 			os.Exit(exitError)
 		}
 
-		if len(records) == 0 {
-			fmt.Printf("No history found for service: %s\n", serviceName)
-			return
+		if err := writeHistory(os.Stdout, historyFormat, args, records); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing history: %v\n", err)
+			os.Exit(exitError)
 		}
+	},
+}
 
-		fmt.Printf("Check History for '%s' (last %d records):\n\n", serviceName, len(records))
-		fmt.Println("Time                 | Status | Response Time | Status Code | Error")
-		fmt.Println("---------------------|--------|---------------|-------------|-------")
+// buildHistoryQuery turns the history arguments and flags into a storage query
+func buildHistoryQuery(services []config.Service, args []string, now time.Time) (storage.HistoryQuery, error) {
+	query := storage.HistoryQuery{
+		MinLatency: historyMinLatency,
+		Limit:      historyLimit,
+	}
+	for _, arg := range args {
+		query.ServiceIDs = append(query.ServiceIDs, resolveServiceID(services, arg))
+	}
 
-		for _, record := range records {
-			status := "UP  "
-			if !record.IsUp {
-				status = "DOWN"
-			}
+	switch strings.ToLower(historyStatus) {
+	case "", "up", "degraded", "down":
+		query.Status = strings.ToUpper(historyStatus)
+	default:
+		return query, fmt.Errorf("invalid --status %q, expected up, degraded or down", historyStatus)
+	}
+
+	if historySince != "" {
+		since, err := parseTimeFlag(historySince, now)
+		if err != nil {
+			return query, fmt.Errorf("invalid --since: %w", err)
+		}
+		query.Since = since
+	}
+	if historyUntil != "" {
+		until, err := parseTimeFlag(historyUntil, now)
+		if err != nil {
+			return query, fmt.Errorf("invalid --until: %w", err)
+		}
+		query.Until = until
+	}
+	return query, nil
+}
+
+// parseTimeFlag parses a point in time given as a date, an RFC 3339
+// timestamp or a time ago such as "24h" or "7d"
+func parseTimeFlag(value string, now time.Time) (time.Time, error) {
+	if ago, err := parseWindow(value); err == nil {
+		return now.Add(-ago), nil
+	}
+	return parseReportTime(value)
+}
+
+// writeHistory writes check records as a table, JSON or CSV
+func writeHistory(w io.Writer, format string, services []string, records []storage.CheckRecord) error {
+	switch format {
+	case "table":
+		writeHistoryTable(w, services, records)
+		return nil
+	case "json":
+		return writeHistoryJSON(w, records)
+	case "csv":
+		return writeHistoryCSV(w, records)
+	}
+	return fmt.Errorf("unknown format %q, expected table, json or csv", format)
+}
+
+func writeHistoryTable(w io.Writer, services []string, records []storage.CheckRecord) {
+	if len(records) == 0 {
+		if len(services) == 0 {
+			fmt.Fprintln(w, "No history found")
+		} else {
+			fmt.Fprintf(w, "No history found for service: %s\n", strings.Join(services, ", "))
+		}
+		return
+	}
 
-			responseTime := fmt.Sprintf("%dms", record.ResponseTimeMs)
-			statusCode := fmt.Sprintf("%d", record.StatusCode)
-			if record.StatusCode == 0 {
-				statusCode = "N/A"
+	// A single service is named in the title, several get their own column
+	serviceColumn := len(services) != 1
+	if serviceColumn {
+		fmt.Fprintf(w, "Check History (last %d records):\n\n", len(records))
+		fmt.Fprintln(w, "Time                 | Service              | Status   | State    | Response Time | Status Code | Error")
+		fmt.Fprintln(w, "---------------------|----------------------|----------|----------|---------------|-------------|-------")
+	} else {
+		fmt.Fprintf(w, "Check History for '%s' (last %d records):\n\n", services[0], len(records))
+		fmt.Fprintln(w, "Time                 | Status   | State    | Response Time | Status Code | Error")
+		fmt.Fprintln(w, "---------------------|----------|----------|---------------|-------------|-------")
+	}
+
+	for _, record := range records {
+
+		state := record.State
+		if state == "" {
+			state = "-"
+		}
+
+		responseTime := fmt.Sprintf("%dms", record.ResponseTimeMs)
+		statusCode := fmt.Sprintf("%d", record.StatusCode)
+		if record.StatusCode == 0 {
+			statusCode = "N/A"
+		}
+
+		errorMsg := record.ErrorMessage
+		if errorMsg == "" {
+			errorMsg = "-"
+		} else if len(errorMsg) > 40 {
+			errorMsg = errorMsg[:37] + "..."
+		}
+		if record.Attempts > 1 {
+			if record.IsUp {
+				errorMsg = fmt.Sprintf("succeeded on attempt %d", record.Attempts)
+			} else {
+				errorMsg = fmt.Sprintf("%s (%d attempts)", errorMsg, record.Attempts)
 			}
+		}
 
-			errorMsg := record.ErrorMessage
-			if errorMsg == "" {
-				errorMsg = "-"
-			} else if len(errorMsg) > 40 {
-				errorMsg = errorMsg[:37] + "..."
+		service := ""
+		if serviceColumn {
+			name := record.ServiceName
+			if len(name) > 20 {
+				name = name[:17] + "..."
 			}
+			service = fmt.Sprintf("%-20s | ", name)
+		}
+
+		fmt.Fprintf(w, "%s | %s%-8s | %-8s | %-13s | %-11s | %s\n",
+			record.CheckedAt.Format("2006-01-02 15:04:05"),
+			service,
+			record.Result(),
+			state,
+			responseTime,
+			statusCode,
+			errorMsg,
+		)
+	}
+}
 
-			fmt.Printf("%s | %s   | %-13s | %-11s | %s\n",
-				record.CheckedAt.Format("2006-01-02 15:04:05"),
-				status,
-				responseTime,
-				statusCode,
-				errorMsg,
-			)
+// checkRecordJSON is the JSON form of a stored check
+type checkRecordJSON struct {
+	ServiceID      string    `json:"service_id"`
+	ServiceName    string    `json:"service_name"`
+	URL            string    `json:"url"`
+	CheckedAt      time.Time `json:"checked_at"`
+	Status         string    `json:"status"`
+	State          string    `json:"state,omitempty"`
+	ResponseTimeMs int64     `json:"response_time_ms"`
+	StatusCode     int       `json:"status_code,omitempty"`
+	Error          string    `json:"error,omitempty"`
+	Attempts       int       `json:"attempts"`
+}
+
+func writeHistoryJSON(w io.Writer, records []storage.CheckRecord) error {
+	out := make([]checkRecordJSON, 0, len(records))
+	for _, record := range records {
+		out = append(out, checkRecordJSON{
+			ServiceID:      record.ServiceID,
+			ServiceName:    record.ServiceName,
+			URL:            record.ServiceURL,
+			CheckedAt:      record.CheckedAt,
+			Status:         record.Result(),
+			State:          record.State,
+			ResponseTimeMs: record.ResponseTimeMs,
+			StatusCode:     record.StatusCode,
+			Error:          record.ErrorMessage,
+			Attempts:       record.Attempts,
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(out)
+}
+
+func writeHistoryCSV(w io.Writer, records []storage.CheckRecord) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"checked_at", "service_id", "service_name", "url", "status", "state", "response_time_ms", "status_code", "error", "attempts"})
+
+	for _, record := range records {
+		writer.Write([]string{
+			record.CheckedAt.UTC().Format(time.RFC3339),
+			record.ServiceID,
+			record.ServiceName,
+			record.ServiceURL,
+			record.Result(),
+			record.State,
+			strconv.FormatInt(record.ResponseTimeMs, 10),
+			strconv.Itoa(record.StatusCode),
+			record.ErrorMessage,
+			strconv.Itoa(record.Attempts),
+		})
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// resolveServiceID returns the ID of the configured service with the given
+// name or ID, or the argument itself if no such service is configured
+func resolveServiceID(services []config.Service, nameOrID string) string {
+	for _, service := range services {
+		if service.Key() == nameOrID || service.Name == nameOrID {
+			return service.Key()
 		}
-	},
+	}
+	return nameOrID
 }
 
 func init() {
 	rootCmd.AddCommand(historyCmd)
-	historyCmd.Flags().IntVarP(&historyLimit, "limit", "l", 50, "Number of records to display")
+	historyCmd.Flags().IntVarP(&historyLimit, "limit", "l", 50, "Number of records to display (0 for all)")
+	historyCmd.Flags().StringVar(&historySince, "since", "", "Only checks at or after this time")
+	historyCmd.Flags().StringVar(&historyUntil, "until", "", "Only checks before this time")
+	historyCmd.Flags().StringVar(&historyStatus, "status", "", "Only checks with this result: up, degraded or down")
+	historyCmd.Flags().DurationVar(&historyMinLatency, "min-latency", 0, "Only checks at least this slow, e.g. 500ms")
+	historyCmd.Flags().StringVarP(&historyFormat, "output", "o", "table", "Output format: table, json or csv")
 }
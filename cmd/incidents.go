@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/0xReLogic/SENTINEL/storage"
+	"github.com/spf13/cobra"
+)
+
+var (
+	incidentsLimit int
+	incidentID     int64
+)
+
+var incidentsCmd = &cobra.Command{
+	Use:   "incidents [service-name-or-id]",
+	Short: "View recorded incidents",
+	Long:  "Display the outages recorded in the database, newest first, with their start, end, duration and first error. Without a service the incidents of all services are shown; --id lists the checks of a single incident.",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := loadConfig(configPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+			os.Exit(exitConfigError)
+		}
+
+		if cfg.Storage.Type == "" || cfg.Storage.Path == "" {
+			fmt.Fprintln(os.Stderr, "Storage not configured. Please configure storage in sentinel.yaml")
+			os.Exit(exitConfigError)
+		}
+
+		store, err := storage.NewSQLiteStorage(cfg.Storage.Path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error opening storage: %v\n", err)
+			os.Exit(exitError)
+		}
+		defer store.Close()
+
+		if incidentID != 0 {
+			printIncidentChecks(store, incidentID)
+			return
+		}
+
+		var serviceID string
+		if len(args) == 1 {
+			serviceID = resolveServiceID(cfg.Services, args[0])
+		}
+
+		incidents, err := store.GetIncidents(serviceID, incidentsLimit)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error retrieving incidents: %v\n", err)
+			os.Exit(exitError)
+		}
+
+		if len(incidents) == 0 {
+			fmt.Println("No incidents found")
+			return
+		}
+
+		fmt.Printf("Incidents (last %d):\n\n", len(incidents))
+		fmt.Println("ID    | Service              | Started             | Ended               | Duration     | Checks | First Error")
+		fmt.Println("------|----------------------|---------------------|---------------------|--------------|--------|------------")
+
+		for _, incident := range incidents {
+			fmt.Println(formatIncidentRow(incident, time.Now()))
+		}
+	},
+}
+
+// formatIncidentRow formats an incident as a row of the incidents table.
+// Open incidents show the time they have lasted so far.
+func formatIncidentRow(incident storage.Incident, now time.Time) string {
+	ended := "ongoing"
+	duration := incident.Duration
+	if incident.Open() {
+		duration = now.Sub(incident.StartedAt).Round(time.Second)
+	} else {
+		ended = incident.EndedAt.Format("2006-01-02 15:04:05")
+	}
+
+	service := incident.ServiceName
+	if len(service) > 20 {
+		service = service[:17] + "..."
+	}
+
+	firstError := incident.FirstError
+	if firstError == "" {
+		firstError = "-"
+	} else if len(firstError) > 40 {
+		firstError = firstError[:37] + "..."
+	}
+
+	return fmt.Sprintf("%-5d | %-20s | %s | %-19s | %-12s | %-6d | %s",
+		incident.ID,
+		service,
+		incident.StartedAt.Format("2006-01-02 15:04:05"),
+		ended,
+		duration,
+		incident.Checks,
+		firstError,
+	)
+}
+
+// printIncidentChecks lists the checks that belong to an incident
+func printIncidentChecks(store storage.Storage, id int64) {
+	records, err := store.GetIncidentChecks(id)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error retrieving incident checks: %v\n", err)
+		os.Exit(exitError)
+	}
+
+	if len(records) == 0 {
+		fmt.Printf("No checks found for incident: %d\n", id)
+		return
+	}
+
+	fmt.Printf("Checks of incident %d (%s):\n\n", id, records[0].ServiceName)
+	fmt.Println("Time                 | Response Time | Status Code | Error")
+	fmt.Println("---------------------|---------------|-------------|-------")
+
+	for _, record := range records {
+		statusCode := fmt.Sprintf("%d", record.StatusCode)
+		if record.StatusCode == 0 {
+			statusCode = "N/A"
+		}
+
+		errorMsg := record.ErrorMessage
+		if errorMsg == "" {
+			errorMsg = "-"
+		}
+
+		fmt.Printf("%s | %-13s | %-11s | %s\n",
+			record.CheckedAt.Format("2006-01-02 15:04:05"),
+			fmt.Sprintf("%dms", record.ResponseTimeMs),
+			statusCode,
+			errorMsg,
+		)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(incidentsCmd)
+	incidentsCmd.Flags().IntVarP(&incidentsLimit, "limit", "l", 20, "Number of incidents to display")
+	incidentsCmd.Flags().Int64Var(&incidentID, "id", 0, "Show the checks of the incident with this id")
+}
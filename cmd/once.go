@@ -5,17 +5,26 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/0xReLogic/SENTINEL/storage"
 	"github.com/spf13/cobra"
 )
 
+// onceOutput is the output format of the once command
+var onceOutput string
+
 // onceCmd represents the once command
 var onceCmd = &cobra.Command{
 	Use:   cmdNameOnce,
 	Short: descOnceShort,
 	Long:  fmt.Sprintf(descOnceLong, exitSuccess, exitError, exitConfigError),
 	Run: func(cmd *cobra.Command, args []string) {
+		if !isValidOnceOutput(onceOutput) {
+			fmt.Fprintf(os.Stderr, "Error: unknown output format %q, expected text, json, junit or tap\n", onceOutput)
+			os.Exit(exitConfigError)
+		}
+
 		cfg, err := loadConfig(configPath)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, errLoadingConfig, err)
@@ -35,6 +44,21 @@ var onceCmd = &cobra.Command{
 
 		// Create StateManager to handle notifications correctly even on a single run.
 		stateManager := NewStateManager()
+
+		// Machine-readable formats replace the status lines on stdout, the
+		// exit code stays the same
+		if onceOutput != outputText {
+			started := time.Now()
+			statuses := runChecks(cfg, stateManager, store, nil)
+			if err := writeCheckResults(os.Stdout, onceOutput, statuses, started, time.Since(started)); err != nil {
+				fmt.Fprintf(os.Stderr, "Error writing results: %v\n", err)
+			}
+			if allUp(statuses) {
+				os.Exit(exitSuccess)
+			}
+			os.Exit(exitError)
+		}
+
 		allServicesUp := runChecksAndGetStatus(cfg, stateManager, store)
 
 		// Exit with the correct status code based on the result.
@@ -50,4 +74,5 @@ var onceCmd = &cobra.Command{
 
 func init() {
 	rootCmd.AddCommand(onceCmd)
+	onceCmd.Flags().StringVarP(&onceOutput, "output", "o", outputText, "Output format: text, json, junit or tap")
 }
\ No newline at end of file
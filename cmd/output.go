@@ -0,0 +1,244 @@
+package cmd
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/0xReLogic/SENTINEL/checker"
+)
+
+// Output formats of the once command
+const (
+	outputText  = "text"
+	outputJSON  = "json"
+	outputJUnit = "junit"
+	outputTAP   = "tap"
+)
+
+// isValidOnceOutput reports whether format is a supported once output format
+func isValidOnceOutput(format string) bool {
+	switch format {
+	case outputText, outputJSON, outputJUnit, outputTAP:
+		return true
+	}
+	return false
+}
+
+// writeCheckResults writes the statuses of a single run in a machine-readable
+// format. duration is the time the whole run took.
+func writeCheckResults(w io.Writer, format string, statuses []checker.ServiceStatus, started time.Time, duration time.Duration) error {
+	switch format {
+	case outputJSON:
+		return writeCheckResultsJSON(w, statuses, started, duration)
+	case outputJUnit:
+		return writeCheckResultsJUnit(w, statuses, started, duration)
+	case outputTAP:
+		return writeCheckResultsTAP(w, statuses)
+	}
+	return fmt.Errorf("unknown format %q, expected text, json, junit or tap", format)
+}
+
+// checkResultJSON is the JSON form of a single check
+type checkResultJSON struct {
+	ID             string `json:"id"`
+	Name           string `json:"name"`
+	URL            string `json:"url"`
+	Status         string `json:"status"`
+	Up             bool   `json:"up"`
+	ResponseTimeMs int64  `json:"response_time_ms"`
+	StatusCode     int    `json:"status_code,omitempty"`
+	Error          string `json:"error,omitempty"`
+	Attempts       int    `json:"attempts,omitempty"`
+}
+
+// checkResultsJSON is the JSON form of a single run
+type checkResultsJSON struct {
+	StartedAt  time.Time         `json:"started_at"`
+	DurationMs int64             `json:"duration_ms"`
+	AllUp      bool              `json:"all_up"`
+	Services   []checkResultJSON `json:"services"`
+}
+
+func writeCheckResultsJSON(w io.Writer, statuses []checker.ServiceStatus, started time.Time, duration time.Duration) error {
+	out := checkResultsJSON{
+		StartedAt:  started.UTC(),
+		DurationMs: duration.Milliseconds(),
+		AllUp:      allUp(statuses),
+		Services:   make([]checkResultJSON, 0, len(statuses)),
+	}
+	for _, status := range statuses {
+		result := checkResultJSON{
+			ID:             status.ID,
+			Name:           status.Name,
+			URL:            status.URL,
+			Status:         status.Result(),
+			Up:             status.IsUp,
+			ResponseTimeMs: status.ResponseTime.Milliseconds(),
+			StatusCode:     status.StatusCode,
+			Attempts:       len(status.Attempts),
+		}
+		if status.Error != nil {
+			result.Error = status.Error.Error()
+		}
+		out.Services = append(out.Services, result)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(out)
+}
+
+// JUnit XML report, in the subset understood by common CI systems
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Time      string          `xml:"time,attr"`
+	Timestamp string          `xml:"timestamp,attr"`
+	Cases     []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Details string `xml:",chardata"`
+}
+
+func writeCheckResultsJUnit(w io.Writer, statuses []checker.ServiceStatus, started time.Time, duration time.Duration) error {
+	suite := junitTestSuite{
+		Name:      appName,
+		Tests:     len(statuses),
+		Time:      junitSeconds(duration),
+		Timestamp: started.UTC().Format("2006-01-02T15:04:05"),
+	}
+
+	for _, status := range statuses {
+		testCase := junitTestCase{
+			Name:      status.Name,
+			ClassName: appName + "." + status.ID,
+			Time:      junitSeconds(status.ResponseTime),
+			SystemOut: status.String(),
+		}
+		if !status.IsUp {
+			suite.Failures++
+			testCase.Failure = &junitFailure{
+				Message: failureMessage(status),
+				Type:    status.Result(),
+				Details: failureDetails(status),
+			}
+		}
+		suite.Cases = append(suite.Cases, testCase)
+	}
+
+	report := junitTestSuites{
+		Name:     appName,
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Time:     suite.Time,
+		Suites:   []junitTestSuite{suite},
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// junitSeconds formats a duration as JUnit seconds
+func junitSeconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
+
+// failureMessage returns a one-line reason for a failed check
+func failureMessage(status checker.ServiceStatus) string {
+	if status.Error != nil {
+		return status.Error.Error()
+	}
+	if status.StatusCode != 0 {
+		return fmt.Sprintf("unexpected status code %d", status.StatusCode)
+	}
+	return "service is DOWN"
+}
+
+// failureDetails lists what is known about a failed check, one fact per line
+func failureDetails(status checker.ServiceStatus) string {
+	details := []string{"url: " + status.URL}
+	if status.StatusCode != 0 {
+		details = append(details, fmt.Sprintf("status code: %d", status.StatusCode))
+	}
+	if status.Error != nil {
+		details = append(details, "error: "+status.Error.Error())
+	}
+	details = append(details, fmt.Sprintf("response time: %d ms", status.ResponseTime.Milliseconds()))
+	if summary := status.AttemptSummary(); summary != "" {
+		details = append(details, summary)
+	}
+	return strings.Join(details, "\n")
+}
+
+// writeCheckResultsTAP writes a Test Anything Protocol (version 13) report
+// with a YAML diagnostic block for every failed check
+func writeCheckResultsTAP(w io.Writer, statuses []checker.ServiceStatus) error {
+	var b strings.Builder
+	b.WriteString("TAP version 13\n")
+	fmt.Fprintf(&b, "1..%d\n", len(statuses))
+
+	for i, status := range statuses {
+		if status.IsUp {
+			fmt.Fprintf(&b, "ok %d - %s\n", i+1, tapDescription(status))
+			continue
+		}
+		fmt.Fprintf(&b, "not ok %d - %s\n", i+1, tapDescription(status))
+		b.WriteString("  ---\n")
+		fmt.Fprintf(&b, "  message: %q\n", failureMessage(status))
+		fmt.Fprintf(&b, "  url: %q\n", status.URL)
+		if status.StatusCode != 0 {
+			fmt.Fprintf(&b, "  status_code: %d\n", status.StatusCode)
+		}
+		fmt.Fprintf(&b, "  response_time_ms: %d\n", status.ResponseTime.Milliseconds())
+		if len(status.Attempts) > 1 {
+			fmt.Fprintf(&b, "  attempts: %d\n", len(status.Attempts))
+		}
+		b.WriteString("  ...\n")
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// tapDescription describes a check on its TAP test line. A '#' would start a
+// TAP directive, so it is escaped in service names.
+func tapDescription(status checker.ServiceStatus) string {
+	name := strings.ReplaceAll(status.Name, "#", "\\#")
+	if status.StatusCode != 0 {
+		return fmt.Sprintf("%s (%s, %d ms, HTTP %d)", name, status.Result(), status.ResponseTime.Milliseconds(), status.StatusCode)
+	}
+	return fmt.Sprintf("%s (%s, %d ms)", name, status.Result(), status.ResponseTime.Milliseconds())
+}
@@ -0,0 +1,314 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/0xReLogic/SENTINEL/storage"
+	"github.com/spf13/cobra"
+)
+
+var (
+	reportWindow string
+	reportFrom   string
+	reportTo     string
+	reportFormat string
+)
+
+var reportCmd = &cobra.Command{
+	Use:   "report [service-name-or-id...]",
+	Short: "Report uptime and response times over a time window",
+	Long: `Compute uptime, incidents, MTTR, MTBF and p50/p95/p99 response times per service from storage.
+The window is the last --window (24h, 7d, 30d or any duration), or --from until --to (default now).
+Dates are given as 2006-01-02 (midnight UTC) or RFC 3339; --to is exclusive.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := loadConfig(configPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+			os.Exit(exitConfigError)
+		}
+
+		if cfg.Storage.Type == "" || cfg.Storage.Path == "" {
+			fmt.Fprintln(os.Stderr, "Storage not configured. Please configure storage in sentinel.yaml")
+			os.Exit(exitConfigError)
+		}
+
+		from, to, err := parseReportRange(reportWindow, reportFrom, reportTo, time.Now())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(exitError)
+		}
+
+		store, err := storage.NewSQLiteStorage(cfg.Storage.Path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error opening storage: %v\n", err)
+			os.Exit(exitError)
+		}
+		defer store.Close()
+
+		reports, err := store.GetReport(from, to)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error computing report: %v\n", err)
+			os.Exit(exitError)
+		}
+
+		if len(args) > 0 {
+			wanted := make(map[string]bool)
+			for _, arg := range args {
+				wanted[resolveServiceID(cfg.Services, arg)] = true
+			}
+			var filtered []storage.ServiceReport
+			for _, report := range reports {
+				if wanted[report.ServiceID] {
+					filtered = append(filtered, report)
+				}
+			}
+			reports = filtered
+		}
+
+		if err := writeReport(os.Stdout, reportFormat, reports, from, to); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing report: %v\n", err)
+			os.Exit(exitError)
+		}
+	},
+}
+
+// parseReportRange returns the time range of a report: from..to when from is
+// set, otherwise the window up to now
+func parseReportRange(window, from, to string, now time.Time) (time.Time, time.Time, error) {
+	end := now
+	if to != "" {
+		t, err := parseReportTime(to)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid --to: %w", err)
+		}
+		end = t
+	}
+
+	if from != "" {
+		start, err := parseReportTime(from)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid --from: %w", err)
+		}
+		if !start.Before(end) {
+			return time.Time{}, time.Time{}, fmt.Errorf("--from must be before --to")
+		}
+		return start, end, nil
+	}
+
+	d, err := parseWindow(window)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid --window: %w", err)
+	}
+	return end.Add(-d), end, nil
+}
+
+// parseWindow parses a report window, either a number of days such as "7d" or
+// a Go duration such as "24h"
+func parseWindow(window string) (time.Duration, error) {
+	var d time.Duration
+	if days, ok := strings.CutSuffix(window, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("%q is not a number of days", window)
+		}
+		d = time.Duration(n) * 24 * time.Hour
+	} else {
+		parsed, err := time.ParseDuration(window)
+		if err != nil {
+			return 0, err
+		}
+		d = parsed
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("%q must be positive", window)
+	}
+	return d, nil
+}
+
+// parseReportTime parses a date (midnight UTC) or an RFC 3339 timestamp
+func parseReportTime(value string) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// writeReport writes service reports as a table, JSON or CSV
+func writeReport(w io.Writer, format string, reports []storage.ServiceReport, from, to time.Time) error {
+	switch format {
+	case "table":
+		writeReportTable(w, reports, from, to)
+		return nil
+	case "json":
+		return writeReportJSON(w, reports, from, to)
+	case "csv":
+		return writeReportCSV(w, reports, to.Sub(from))
+	}
+	return fmt.Errorf("unknown format %q, expected table, json or csv", format)
+}
+
+func writeReportTable(w io.Writer, reports []storage.ServiceReport, from, to time.Time) {
+	fmt.Fprintf(w, "Report from %s to %s:\n\n", from.UTC().Format(timestampFormat), to.UTC().Format(timestampFormat))
+	if len(reports) == 0 {
+		fmt.Fprintln(w, "No checks found in this window")
+		return
+	}
+
+	fmt.Fprintln(w, "Service              | Uptime   | Incidents | MTTR       | MTBF       | p50      | p95      | p99")
+	fmt.Fprintln(w, "---------------------|----------|-----------|------------|------------|----------|----------|---------")
+
+	window := to.Sub(from)
+	for _, report := range reports {
+		name := report.ServiceName
+		if len(name) > 20 {
+			name = name[:17] + "..."
+		}
+		fmt.Fprintf(w, "%-20s | %-8s | %-9d | %-10s | %-10s | %-8s | %-8s | %s\n",
+			name,
+			fmt.Sprintf("%.3f%%", report.Uptime()),
+			report.Incidents,
+			formatReportDuration(report.MTTR()),
+			formatReportDuration(report.MTBF(window)),
+			formatLatency(report.P50, report.Samples),
+			formatLatency(report.P95, report.Samples),
+			formatLatency(report.P99, report.Samples),
+		)
+	}
+}
+
+// formatReportDuration shows durations below a second in milliseconds and
+// longer ones rounded to the second, or "-" if there is no value
+func formatReportDuration(d time.Duration) string {
+	switch {
+	case d == 0:
+		return "-"
+	case d < time.Second:
+		return fmt.Sprintf("%dms", d.Milliseconds())
+	}
+	return d.Round(time.Second).String()
+}
+
+// formatLatency is formatReportDuration for a response time percentile,
+// which is "-" only without samples and "0ms" for sub-millisecond responses
+func formatLatency(d time.Duration, samples int) string {
+	if samples > 0 && d < time.Millisecond {
+		return "0ms"
+	}
+	return formatReportDuration(d)
+}
+
+// reportJSON is the JSON form of a report
+type reportJSON struct {
+	From     time.Time           `json:"from"`
+	To       time.Time           `json:"to"`
+	Services []serviceReportJSON `json:"services"`
+}
+
+// serviceReportJSON is the JSON form of a service report. MTTR and MTBF are
+// null when there is no incident to compute them from, and the percentiles
+// when there is no successful check.
+type serviceReportJSON struct {
+	ID            string   `json:"id"`
+	Name          string   `json:"name"`
+	Checks        int      `json:"checks"`
+	UptimePercent float64  `json:"uptime_percent"`
+	Incidents     int      `json:"incidents"`
+	MTTRSeconds   *float64 `json:"mttr_seconds"`
+	MTBFSeconds   *float64 `json:"mtbf_seconds"`
+	P50Ms         *int64   `json:"p50_ms"`
+	P95Ms         *int64   `json:"p95_ms"`
+	P99Ms         *int64   `json:"p99_ms"`
+}
+
+func writeReportJSON(w io.Writer, reports []storage.ServiceReport, from, to time.Time) error {
+	out := reportJSON{From: from.UTC(), To: to.UTC(), Services: []serviceReportJSON{}}
+	for _, report := range reports {
+		out.Services = append(out.Services, serviceReportJSON{
+			ID:            report.ServiceID,
+			Name:          report.ServiceName,
+			Checks:        report.Checks,
+			UptimePercent: report.Uptime(),
+			Incidents:     report.Incidents,
+			MTTRSeconds:   optionalSeconds(report.MTTR()),
+			MTBFSeconds:   optionalSeconds(report.MTBF(to.Sub(from))),
+			P50Ms:         optionalMilliseconds(report.P50, report.Samples),
+			P95Ms:         optionalMilliseconds(report.P95, report.Samples),
+			P99Ms:         optionalMilliseconds(report.P99, report.Samples),
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(out)
+}
+
+// optionalSeconds returns d in seconds, or nil for a zero duration
+func optionalSeconds(d time.Duration) *float64 {
+	if d == 0 {
+		return nil
+	}
+	seconds := d.Seconds()
+	return &seconds
+}
+
+// optionalMilliseconds returns d in milliseconds, or nil without samples
+func optionalMilliseconds(d time.Duration, samples int) *int64 {
+	if samples == 0 {
+		return nil
+	}
+	ms := d.Milliseconds()
+	return &ms
+}
+
+func writeReportCSV(w io.Writer, reports []storage.ServiceReport, window time.Duration) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"id", "name", "checks", "uptime_percent", "incidents", "mttr_seconds", "mtbf_seconds", "p50_ms", "p95_ms", "p99_ms"})
+
+	// seconds leaves a cell empty when there is no value
+	seconds := func(d time.Duration) string {
+		if d == 0 {
+			return ""
+		}
+		return strconv.FormatFloat(d.Seconds(), 'f', -1, 64)
+	}
+	// milliseconds leaves a cell empty when there are no samples
+	milliseconds := func(d time.Duration, samples int) string {
+		if samples == 0 {
+			return ""
+		}
+		return strconv.FormatInt(d.Milliseconds(), 10)
+	}
+
+	for _, report := range reports {
+		writer.Write([]string{
+			report.ServiceID,
+			report.ServiceName,
+			strconv.Itoa(report.Checks),
+			strconv.FormatFloat(report.Uptime(), 'f', 3, 64),
+			strconv.Itoa(report.Incidents),
+			seconds(report.MTTR()),
+			seconds(report.MTBF(window)),
+			milliseconds(report.P50, report.Samples),
+			milliseconds(report.P95, report.Samples),
+			milliseconds(report.P99, report.Samples),
+		})
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func init() {
+	rootCmd.AddCommand(reportCmd)
+	reportCmd.Flags().StringVarP(&reportWindow, "window", "w", "24h", "Report window up to now: 24h, 7d, 30d or any duration")
+	reportCmd.Flags().StringVar(&reportFrom, "from", "", "Start of a custom range, overrides --window")
+	reportCmd.Flags().StringVar(&reportTo, "to", "", "End of the range (default now)")
+	reportCmd.Flags().StringVarP(&reportFormat, "output", "o", "table", "Output format: table, json or csv")
+}
@@ -4,13 +4,16 @@ package cmd
 import (
 	"fmt"
 	"log"
-	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"time"
+	"unicode"
 
 	"github.com/0xReLogic/SENTINEL/checker"
 	"github.com/0xReLogic/SENTINEL/config"
+	"github.com/0xReLogic/SENTINEL/metrics"
 	"github.com/0xReLogic/SENTINEL/notifier"
 	"github.com/0xReLogic/SENTINEL/storage"
 	"github.com/spf13/cobra"
@@ -27,9 +30,15 @@ const (
 	// NoAction means no notification should be sent.
 	// NotifyDown means a "service down" notification should be sent.
 	// NotifyRecovery means a "service recovered" notification should be sent.
+	// NotifyCertExpiring means a "certificate expiring" notification should be sent.
+	// NotifyDegraded means a "service degraded" notification should be sent.
+	// NotifyStillDown means a "service still down" reminder should be sent.
 	NoAction ActionType = iota
 	NotifyDown
 	NotifyRecovery
+	NotifyCertExpiring
+	NotifyDegraded
+	NotifyStillDown
 )
 
 // NotificationAction represents the decision made by the StateManager about whether a notification should be sent.
@@ -40,27 +49,100 @@ type NotificationAction struct {
 }
 
 // StateManager encapsulates the state and logic for tracking service statuses over time.
-// NotificationConfig interface for generic notification handling
-type NotificationConfig interface {
-	IsEnabled() bool
-	GetNotifyOn() []string
+// The state of a service is tracked once per check, while notification
+// throttling is tracked per channel. It is safe for concurrent use: every
+// service has its own lock, so checks of different services never wait on
+// each other.
+type StateManager struct {
+	services sync.Map // service ID -> *serviceTracker
+
+	// now returns the current time; tests replace it to control the clock
+	now func() time.Time
 }
 
-type StateManager struct {
-	serviceState         map[string]bool
-	lastNotificationTime map[string]time.Time
-	serviceDownSince     map[string]time.Time
+// serviceTracker holds the tracked state of a single service, guarded by mu
+type serviceTracker struct {
+	mu               sync.Mutex
+	state            string // last state seen by Transition, empty before the first check
+	confirmed        string // confirmed state from ApplyThresholds, empty before the first check
+	streak           int    // consecutive results differing from confirmed
+	downSince        time.Time
+	certWarnedFor    time.Time
+	lastNotification map[string]time.Time // per channel
+	reminders        map[string]int       // reminders sent per channel during the current outage
+	escalationStep   int                  // escalation steps sent during the current outage
 }
 
 // NewStateManager creates and initializes a new StateManager.
 func NewStateManager() *StateManager {
-	return &StateManager{
-		serviceState:         make(map[string]bool),
-		lastNotificationTime: make(map[string]time.Time),
-		serviceDownSince:     make(map[string]time.Time),
+	return &StateManager{now: time.Now}
+}
+
+// clock returns the current time of the state manager
+func (sm *StateManager) clock() time.Time {
+	if sm.now == nil {
+		return time.Now()
+	}
+	return sm.now()
+}
+
+// tracker returns the tracker of a service, creating it on first use
+func (sm *StateManager) tracker(id string) *serviceTracker {
+	if t, ok := sm.services.Load(id); ok {
+		return t.(*serviceTracker)
+	}
+	t, _ := sm.services.LoadOrStore(id, &serviceTracker{
+		lastNotification: make(map[string]time.Time),
+		reminders:        make(map[string]int),
+	})
+	return t.(*serviceTracker)
+}
+
+// Restore loads persisted service states, so transitions and downtime carry
+// over from before a restart. Services checked since are left untouched.
+func (sm *StateManager) Restore(states []storage.ServiceState) {
+	for _, saved := range states {
+		t := sm.tracker(saved.ServiceID)
+		t.mu.Lock()
+		if t.state == "" {
+			t.state = saved.State
+			t.confirmed = saved.State
+			t.downSince = saved.DownSince
+			t.escalationStep = saved.EscalationStep
+			for channel, notifiedAt := range saved.LastNotification {
+				t.lastNotification[channel] = notifiedAt
+			}
+			for channel, count := range saved.Reminders {
+				t.reminders[channel] = count
+			}
+		}
+		t.mu.Unlock()
 	}
 }
 
+// Snapshot returns the current state of a service for persisting
+func (sm *StateManager) Snapshot(id string) storage.ServiceState {
+	t := sm.tracker(id)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	saved := storage.ServiceState{
+		ServiceID:        id,
+		State:            t.state,
+		DownSince:        t.downSince,
+		EscalationStep:   t.escalationStep,
+		LastNotification: make(map[string]time.Time, len(t.lastNotification)),
+		Reminders:        make(map[string]int, len(t.reminders)),
+	}
+	for channel, notifiedAt := range t.lastNotification {
+		saved.LastNotification[channel] = notifiedAt
+	}
+	for channel, count := range t.reminders {
+		saved.Reminders[channel] = count
+	}
+	return saved
+}
+
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use:   appName,
@@ -68,39 +150,220 @@ var rootCmd = &cobra.Command{
 	Long:  fmt.Sprintf(descLong, appRepository),
 }
 
-func (sm *StateManager) ProcessStatus(status checker.ServiceStatus, service config.Service, cfg config.TelegramConfig) NotificationAction {
-	checkTime := time.Now()
-	previousIsUp, exists := sm.serviceState[status.URL]
-	// Defer the state update so it happens regardless of how the function exits.
-	defer func() { sm.serviceState[status.URL] = status.IsUp }()
+// ApplyThresholds records a check result and sets status.State to the confirmed
+// service state (UP, DEGRADED or DOWN). The state only changes after
+// FailureThreshold consecutive failed or degraded results, or SuccessThreshold
+// consecutive successes; the first check of a service establishes its state
+// immediately.
+func (sm *StateManager) ApplyThresholds(status checker.ServiceStatus, service config.Service) checker.ServiceStatus {
+	result := status.Result()
+	t := sm.tracker(status.ID)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	switch {
+	case t.confirmed == "":
+		t.confirmed = result
+		t.streak = 0
+	case result == t.confirmed:
+		t.streak = 0
+	default:
+		t.streak++
+		threshold := service.FailureThreshold
+		if result == checker.StateUp {
+			threshold = service.SuccessThreshold
+		}
+		if t.streak >= threshold {
+			t.confirmed = result
+			t.streak = 0
+		}
+	}
 
-	// 1. Determine the exact state transition
-	isDownTransition := exists && previousIsUp && !status.IsUp
-	isRecoveryTransition := exists && !previousIsUp && status.IsUp
+	status.State = t.confirmed
+	return status
+}
 
-	// 2. If there's no state change, we are done.
-	if !isDownTransition && !isRecoveryTransition {
+// Transition records the confirmed state of a check and returns the state
+// change it caused, if any. It must be called exactly once per check; the
+// result is then passed to ChannelAction for every enabled channel.
+func (sm *StateManager) Transition(status checker.ServiceStatus) NotificationAction {
+	checkTime := sm.clock()
+	state := confirmedState(status)
+	t := sm.tracker(status.ID)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	previousState := t.state
+	t.state = state
+
+	// The first check establishes the state without notifying
+	if previousState == "" {
+		if state == checker.StateDown {
+			t.downSince = checkTime
+		}
 		return NotificationAction{Action: NoAction}
 	}
-	// 3. Check for throttling. If a notification was sent recently, we are done.
-	isThrottled := time.Since(sm.lastNotificationTime[status.URL]) < service.Interval
-	if isThrottled {
+
+	switch {
+	case previousState != checker.StateDown && state == checker.StateDown:
+		t.downSince = checkTime
+		t.reminders = make(map[string]int)
+		return NotificationAction{Action: NotifyDown}
+	case previousState == checker.StateDown && state != checker.StateDown:
+		downtime := checkTime.Sub(t.downSince)
+		t.downSince = time.Time{}
+		return NotificationAction{Action: NotifyRecovery, Downtime: downtime}
+	case previousState == checker.StateUp && state == checker.StateDegraded:
+		return NotificationAction{Action: NotifyDegraded}
+	}
+	return NotificationAction{Action: NoAction}
+}
+
+// ChannelAction decides whether a transition is sent to a notification
+// channel. The transition must be one of the channel's notify_on events and
+// the channel must not have notified about the service within its interval.
+func (sm *StateManager) ChannelAction(channel string, notifyOn []string, transition NotificationAction, status checker.ServiceStatus, service config.Service) NotificationAction {
+	event := notifyOnEvent(transition.Action)
+	if event == "" || !contains(notifyOn, event) {
 		return NotificationAction{Action: NoAction}
 	}
-	// Handle a RECOVERY notification
-	if isRecoveryTransition && contains(cfg.NotifyOn, "recovery") {
-		sm.lastNotificationTime[status.URL] = checkTime
-		downtime := time.Since(sm.serviceDownSince[status.URL])
-		delete(sm.serviceDownSince, status.URL)
-		return NotificationAction{Action: NotifyRecovery, Downtime: downtime}
+
+	t := sm.tracker(status.ID)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := sm.clock()
+	if now.Sub(t.lastNotification[channel]) < service.Interval {
+		return NotificationAction{Action: NoAction}
 	}
-	// Handle a DOWN notification
-	if isDownTransition && contains(cfg.NotifyOn, "down") {
-		sm.lastNotificationTime[status.URL] = checkTime
-		sm.serviceDownSince[status.URL] = checkTime
-		return NotificationAction{Action: NotifyDown}
+	t.lastNotification[channel] = now
+	return transition
+}
+
+// ReminderAction decides whether a "still down" reminder is sent to a
+// notification channel. Reminders follow a DOWN alert the channel sent during
+// the current outage, every RepeatInterval and at most MaxReminders times.
+func (sm *StateManager) ReminderAction(channel string, reminders config.Reminders, status checker.ServiceStatus) NotificationAction {
+	if reminders.RepeatInterval <= 0 {
+		return NotificationAction{Action: NoAction}
 	}
-	return NotificationAction{Action: NoAction}
+
+	t := sm.tracker(status.ID)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := sm.clock()
+	lastNotified := t.lastNotification[channel]
+	switch {
+	case t.state != checker.StateDown || lastNotified.Before(t.downSince):
+		return NotificationAction{Action: NoAction}
+	case reminders.MaxReminders > 0 && t.reminders[channel] >= reminders.MaxReminders:
+		return NotificationAction{Action: NoAction}
+	case now.Sub(lastNotified) < reminders.RepeatInterval:
+		return NotificationAction{Action: NoAction}
+	}
+
+	t.lastNotification[channel] = now
+	t.reminders[channel]++
+	return NotificationAction{Action: NotifyStillDown, Downtime: now.Sub(t.downSince)}
+}
+
+// EscalationAlert is an alert for one step of an escalation policy
+type EscalationAlert struct {
+	Step  config.EscalationStep
+	Index int // position of Step in the policy
+	NotificationAction
+}
+
+// EscalationActions decides which steps of an escalation policy alert about
+// a check. Each step is sent once per outage, as soon as the service has been
+// down for the step's After delay: the first as a DOWN alert, later ones as
+// "still down" alerts. On recovery every step that was sent is returned
+// again, so everyone who was paged hears about the recovery. The channels of
+// the returned steps count as notified for reminders.
+func (sm *StateManager) EscalationActions(policy config.EscalationPolicy, transition NotificationAction, status checker.ServiceStatus) []EscalationAlert {
+	t := sm.tracker(status.ID)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var alerts []EscalationAlert
+	if transition.Action == NotifyRecovery {
+		for i, step := range policy.Steps[:min(t.escalationStep, len(policy.Steps))] {
+			alerts = append(alerts, EscalationAlert{Step: step, Index: i, NotificationAction: transition})
+		}
+		t.escalationStep = 0
+		return alerts
+	}
+	if t.state != checker.StateDown {
+		return nil
+	}
+	if transition.Action == NotifyDown {
+		t.escalationStep = 0
+	}
+
+	now := sm.clock()
+	downtime := now.Sub(t.downSince)
+	for t.escalationStep < len(policy.Steps) && downtime >= policy.Steps[t.escalationStep].After {
+		step := policy.Steps[t.escalationStep]
+		action := NotificationAction{Action: NotifyStillDown, Downtime: downtime}
+		if t.escalationStep == 0 {
+			action = NotificationAction{Action: NotifyDown}
+		}
+		alerts = append(alerts, EscalationAlert{Step: step, Index: t.escalationStep, NotificationAction: action})
+		for _, channel := range step.Channels {
+			t.lastNotification[channel] = now
+		}
+		t.escalationStep++
+	}
+	return alerts
+}
+
+// ProcessStatus records a check and decides the notification for a single
+// Telegram channel. With several channels, call Transition once and
+// ChannelAction for each channel instead.
+func (sm *StateManager) ProcessStatus(status checker.ServiceStatus, service config.Service, cfg config.TelegramConfig) NotificationAction {
+	return sm.ChannelAction(config.ChannelTelegram, cfg.NotifyOn, sm.Transition(status), status, service)
+}
+
+// notifyOnEvent returns the notify_on value that enables an action
+func notifyOnEvent(action ActionType) string {
+	switch action {
+	case NotifyDown:
+		return "down"
+	case NotifyRecovery:
+		return "recovery"
+	case NotifyDegraded:
+		return "degraded"
+	case NotifyCertExpiring:
+		return "cert_expiry"
+	}
+	return ""
+}
+
+// confirmedState returns the confirmed state of a status, falling back to the
+// raw check result when no thresholds were applied.
+func confirmedState(status checker.ServiceStatus) string {
+	if status.State == "" {
+		return status.Result()
+	}
+	return status.State
+}
+
+// ProcessCertExpiry decides whether a "certificate expiring" notification should be sent.
+// A warning is sent once per certificate, so renewing the certificate re-arms it.
+func (sm *StateManager) ProcessCertExpiry(status checker.ServiceStatus, service config.Service) NotificationAction {
+	if service.CertExpiryWarning <= 0 || !status.TLS.ExpiresWithin(service.CertExpiryWarning, sm.clock()) {
+		return NotificationAction{Action: NoAction}
+	}
+	t := sm.tracker(status.ID)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.certWarnedFor.Equal(status.TLS.NotAfter) {
+		return NotificationAction{Action: NoAction}
+	}
+	t.certWarnedFor = status.TLS.NotAfter
+	return NotificationAction{Action: NotifyCertExpiring}
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -136,117 +399,168 @@ func loadConfig(path string) (*config.Config, error) {
 	return cfg, nil
 }
 
-// notifyServiceDown constructs and sends a 'Service DOWN' notification to Telegram.
-func NotifyServiceDown(cfg config.TelegramConfig, status checker.ServiceStatus, checkTime time.Time) {
-	var errorMsg string
+// downReason describes why a service is down for notifications
+func downReason(status checker.ServiceStatus) string {
 	if status.Error != nil {
-		errorMsg = status.Error.Error()
-	} else {
-		errorMsg = fmt.Sprintf("HTTP Status Code %d", status.StatusCode)
-	}
-	message := notifier.FormatDownMessage(status.Name, status.URL, errorMsg, checkTime)
-
-	log.Printf("INFO: Sending DOWN notification for %s", status.Name)
-
-	err := notifier.SendTelegramNotification(cfg.BotToken, cfg.ChatID, message)
-	if err != nil {
-		log.Printf("ERROR: Failed to send Telegram DOWN notification for %s: %v", status.Name, err)
+		return status.Error.Error()
 	}
+	return fmt.Sprintf("HTTP Status Code %d", status.StatusCode)
 }
 
-// notifyServiceRecovery constructs and sends a 'Service RECOVERED' notification to Telegram.
-func NotifyServiceRecovery(cfg config.TelegramConfig, status checker.ServiceStatus, downtime time.Duration, recoveryTime time.Time) {
-	message := notifier.FormatRecoveryMessage(status.Name, status.URL, downtime, recoveryTime)
-
-	log.Printf("INFO: Sending RECOVERY notification for %s", status.Name)
+// notificationChannels are the notification channels, built once at startup
+// instead of for every check
+type notificationChannels struct {
+	config     config.NotificationConfig
+	enabled    []notifier.Channel
+	escalation map[string][][]notifier.Channel // channels of each step by policy name
+}
 
-	err := notifier.SendTelegramNotification(cfg.BotToken, cfg.ChatID, message)
-	if err != nil {
-		log.Printf("ERROR: Failed to send Telegram RECOVERY notification for %s: %v", status.Name, err)
+// newNotificationChannels builds the enabled channels and the channels of
+// every escalation step, with the step's chat or webhook applied
+func newNotificationChannels(cfg config.NotificationConfig) *notificationChannels {
+	n := &notificationChannels{
+		config:     cfg,
+		enabled:    notifier.Enabled(cfg),
+		escalation: make(map[string][][]notifier.Channel),
 	}
+	for _, policy := range cfg.EscalationPolicies {
+		steps := make([][]notifier.Channel, len(policy.Steps))
+		for i, step := range policy.Steps {
+			stepCfg := step.Apply(cfg)
+			for _, name := range step.Channels {
+				if channel, ok := notifier.Build(name, stepCfg); ok {
+					steps[i] = append(steps[i], channel)
+				}
+			}
+		}
+		n.escalation[policy.Name] = steps
+	}
+	return n
 }
 
-// NotifyDiscordServiceDown sends a Discord notification when a service goes DOWN
-func NotifyDiscordServiceDown(cfg config.DiscordConfig, status checker.ServiceStatus, checkTime time.Time) {
-	var errorMsg string
-	if status.Error != nil {
-		errorMsg = status.Error.Error()
-	} else {
-		errorMsg = fmt.Sprintf("HTTP Status Code %d", status.StatusCode)
+// processNotifications sends the notifications for a service status to every
+// enabled channel
+func processNotifications(notifications *notificationChannels, stateManager *StateManager, status checker.ServiceStatus, service config.Service) {
+	channels := notifications.enabled
+
+	// Certificate warnings are independent of up/down transitions and decided once for all channels
+	if stateManager.ProcessCertExpiry(status, service).Action == NotifyCertExpiring {
+		log.Printf("INFO: Certificate for '%s' expires at %s.", status.Name, status.TLS.NotAfter.Format(timestampFormat))
+		event := newEvent(stateManager, NotificationAction{Action: NotifyCertExpiring}, status, service)
+		for _, channel := range channels {
+			if contains(channel.NotifyOn, "cert_expiry") {
+				sendEvent(channel, event)
+			}
+		}
 	}
-	embed := notifier.FormatDownEmbed(status.Name, status.URL, errorMsg, checkTime)
 
-	log.Printf("INFO: Sending Discord DOWN notification for %s", status.Name)
+	// Track the state change once and fan it out to every enabled channel
+	transition := stateManager.Transition(status)
+	channelTransition := transition
 
-	err := notifier.SendDiscordNotification(cfg.WebhookURL, "", embed)
-	if err != nil {
-		log.Printf("ERROR: Failed to send Discord DOWN notification for %s: %v", status.Name, err)
+	// Outages of services with an escalation policy are alerted by the policy
+	// instead of the channels' notify_on down and recovery events
+	if policy, ok := notifications.config.EscalationPolicy(service.Escalation); ok {
+		if transition.Action == NotifyDown || transition.Action == NotifyRecovery {
+			channelTransition = NotificationAction{Action: NoAction}
+		}
+		for _, alert := range stateManager.EscalationActions(policy, transition, status) {
+			event := newEvent(stateManager, alert.NotificationAction, status, service)
+			for _, channel := range notifications.escalation[policy.Name][alert.Index] {
+				sendEvent(channel, event)
+			}
+		}
 	}
-}
-
-// NotifyDiscordServiceRecovery sends a Discord notification when a service RECOVERS
-func NotifyDiscordServiceRecovery(cfg config.DiscordConfig, status checker.ServiceStatus, downtime time.Duration, recoveryTime time.Time) {
-	embed := notifier.FormatRecoveryEmbed(status.Name, status.URL, downtime, recoveryTime)
 
-	log.Printf("INFO: Sending Discord RECOVERY notification for %s", status.Name)
+	for _, channel := range channels {
+		action := stateManager.ChannelAction(channel.Name, channel.NotifyOn, channelTransition, status, service)
+		if action.Action == NoAction {
+			action = stateManager.ReminderAction(channel.Name, channel.Reminders, status)
+		}
+		if action.Action != NoAction {
+			sendEvent(channel, newEvent(stateManager, action, status, service))
+		}
+	}
+}
 
-	err := notifier.SendDiscordNotification(cfg.WebhookURL, "", embed)
-	if err != nil {
-		log.Printf("ERROR: Failed to send Discord RECOVERY notification for %s: %v", status.Name, err)
+// eventType returns the notifier event of an action
+func eventType(action ActionType) notifier.EventType {
+	switch action {
+	case NotifyDown:
+		return notifier.EventDown
+	case NotifyStillDown:
+		return notifier.EventStillDown
+	case NotifyRecovery:
+		return notifier.EventRecovery
+	case NotifyDegraded:
+		return notifier.EventDegraded
+	case NotifyCertExpiring:
+		return notifier.EventCertExpiring
 	}
+	return ""
 }
 
-// processNotifications handles both Telegram and Discord notifications for a service status
-func processNotifications(cfg *config.Config, stateManager *StateManager, status checker.ServiceStatus, service config.Service) {
-	// Process Telegram notifications
-	if cfg.Notifications.Telegram.Enabled {
-		action := stateManager.ProcessStatus(status, service, cfg.Notifications.Telegram)
-		switch action.Action {
-		case NotifyDown:
-			log.Printf("INFO: Service '%s' is DOWN. Preparing Telegram notification.", status.Name)
-			NotifyServiceDown(cfg.Notifications.Telegram, status, time.Now())
-		case NotifyRecovery:
-			log.Printf("INFO: Service '%s' has RECOVERED. Preparing Telegram notification.", status.Name)
-			NotifyServiceRecovery(cfg.Notifications.Telegram, status, action.Downtime, time.Now())
-		}
+// newEvent describes the notification decided for a service status
+func newEvent(stateManager *StateManager, action NotificationAction, status checker.ServiceStatus, service config.Service) notifier.Event {
+	event := notifier.Event{
+		Type:         eventType(action.Action),
+		ServiceID:    status.ID,
+		ServiceName:  status.Name,
+		URL:          status.URL,
+		StatusCode:   status.StatusCode,
+		Time:         stateManager.clock(),
+		Downtime:     action.Downtime,
+		ResponseTime: status.ResponseTime,
+		Threshold:    service.DegradedThreshold,
 	}
+	if !status.IsUp {
+		event.Error = downReason(status)
+	}
+	if status.TLS != nil {
+		event.CertExpiry = status.TLS.NotAfter
+		event.CertIssuer = status.TLS.Issuer
+	}
+	return event
+}
 
-	// Process Discord notifications
-	if cfg.Notifications.Discord.Enabled {
-		tempCfg := config.TelegramConfig{
-			Enabled:  cfg.Notifications.Discord.Enabled,
-			NotifyOn: cfg.Notifications.Discord.NotifyOn,
-		}
-		action := stateManager.ProcessStatus(status, service, tempCfg)
-		switch action.Action {
-		case NotifyDown:
-			log.Printf("INFO: Service '%s' is DOWN. Preparing Discord notification.", status.Name)
-			NotifyDiscordServiceDown(cfg.Notifications.Discord, status, time.Now())
-		case NotifyRecovery:
-			log.Printf("INFO: Service '%s' has RECOVERED. Preparing Discord notification.", status.Name)
-			NotifyDiscordServiceRecovery(cfg.Notifications.Discord, status, action.Downtime, time.Now())
-		}
+// sendEvent sends an event to a channel, logging the outcome
+func sendEvent(channel notifier.Channel, event notifier.Event) {
+	log.Printf("INFO: Sending %s notification for %s to %s", event.Type, event.ServiceName, channel.Name)
+	if err := channel.Notifier.Notify(event); err != nil {
+		log.Printf("ERROR: Failed to send %s notification for %s to %s: %v", event.Type, event.ServiceName, channel.Name, err)
 	}
 }
 
 // validateServices validates all services in the configuration
 func validateServices(services []config.Service) []error {
 	var errors []error
+	seenIDs := make(map[string]int)
 
 	for i, service := range services {
 		if service.Name == "" {
 			errors = append(errors,
 				fmt.Errorf(errServiceNameReq, i+1))
 		}
-		if service.URL == "" {
+
+		id := service.Key()
+		switch {
+		case id == "" && service.Name != "":
 			errors = append(errors,
-				fmt.Errorf(errServiceURLReq, i+1, service.Name))
-		}
-		// validate URL format if provided
-		if service.URL != "" && !isValidURL(service.URL) {
+				fmt.Errorf(errServiceIDReq, i+1, service.Name))
+		case id != "" && !isValidServiceID(id):
 			errors = append(errors,
-				fmt.Errorf(errServiceURLInvalid, i+1, service.Name, service.URL))
+				fmt.Errorf(errServiceIDInvalid, i+1, service.Name, id))
+		case id != "":
+			if first, ok := seenIDs[id]; ok {
+				errors = append(errors,
+					fmt.Errorf(errServiceIDDuplicate, i+1, service.Name, id, first))
+			} else {
+				seenIDs[id] = i + 1
+			}
 		}
+
+		errors = append(errors, validateRegisteredService(i, service)...)
+
 		if service.Interval <= 0 {
 			errors = append(errors,
 				fmt.Errorf(errServiceIntervalInvalid, i+1, service.Name))
@@ -255,42 +569,194 @@ func validateServices(services []config.Service) []error {
 			errors = append(errors,
 				fmt.Errorf(errServiceTimeoutInvalid, i+1, service.Name))
 		}
+		if service.FailureThreshold < 0 {
+			errors = append(errors,
+				fmt.Errorf(errServiceThresholdInvalid, i+1, service.Name, "failure_threshold"))
+		}
+		if service.SuccessThreshold < 0 {
+			errors = append(errors,
+				fmt.Errorf(errServiceThresholdInvalid, i+1, service.Name, "success_threshold"))
+		}
+		if service.DegradedThreshold < 0 {
+			errors = append(errors,
+				fmt.Errorf(errServiceNotNegative, i+1, service.Name, "degraded_threshold"))
+		}
+		if service.Retries < 0 {
+			errors = append(errors,
+				fmt.Errorf(errServiceNotNegative, i+1, service.Name, "retries"))
+		}
+		if service.RetryDelay < 0 {
+			errors = append(errors,
+				fmt.Errorf(errServiceNotNegative, i+1, service.Name, "retry_delay"))
+		}
+		if service.Retries > 0 && service.Interval > 0 && service.MaxCheckDuration() > service.Interval {
+			errors = append(errors,
+				fmt.Errorf(errServiceRetriesTooLong, i+1, service.Name, service.Retries, service.MaxCheckDuration(), service.Interval))
+		}
 	}
 
 	return errors
 }
 
-// isValidURL checks if a string is a valid HTTP/HTTPS URL
-func isValidURL(urlStr string) bool {
-	u, err := url.Parse(urlStr)
-	return err == nil && u.Scheme != "" && u.Host != "" &&
-		(u.Scheme == schemeHTTP || u.Scheme == schemeHTTPS)
+// isValidServiceID checks that a service ID only uses letters, digits, '.', '-' and '_'
+func isValidServiceID(id string) bool {
+	for _, r := range id {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '.' && r != '-' && r != '_' {
+			return false
+		}
+	}
+	return id != ""
+}
+
+// validateRegisteredService validates a service with the checker registered
+// for its type, if the checker implements checker.Validator
+func validateRegisteredService(i int, service config.Service) []error {
+	c, ok := checker.Lookup(service.Type)
+	if !ok {
+		return []error{fmt.Errorf(errServiceTypeInvalid, i+1, service.Name, service.Type,
+			strings.Join(checker.Types(), ", "))}
+	}
+
+	validator, ok := c.(checker.Validator)
+	if !ok {
+		return nil
+	}
+
+	var errors []error
+	for _, err := range validator.Validate(service) {
+		errors = append(errors,
+			fmt.Errorf(errServiceInvalid, i+1, service.Name, err))
+	}
+	return errors
+}
+
+// validateNotifications validates the settings of every enabled
+// notification channel whose notifier implements notifier.Validator
+func validateNotifications(cfg config.NotificationConfig) []error {
+	var errors []error
+	for _, channel := range notifier.Enabled(cfg) {
+		validator, ok := channel.Notifier.(notifier.Validator)
+		if !ok {
+			continue
+		}
+		for _, err := range validator.Validate() {
+			errors = append(errors, fmt.Errorf(errNotificationInvalid, channel.Name, err))
+		}
+	}
+	return errors
+}
+
+// checkPipeline records and alerts on the checks of run and once alike
+type checkPipeline struct {
+	cfg           *config.Config
+	notifications *notificationChannels
+	stateManager  *StateManager
+	store         storage.Storage // nil without storage
+}
+
+func newCheckPipeline(cfg *config.Config, stateManager *StateManager, store storage.Storage) *checkPipeline {
+	return &checkPipeline{
+		cfg:           cfg,
+		notifications: newNotificationChannels(cfg.Notifications),
+		stateManager:  stateManager,
+		store:         store,
+	}
+}
+
+// process checks a service, confirms its state, saves the check, records
+// metrics, sends the notifications and persists the state, and returns the
+// confirmed status
+func (p *checkPipeline) process(service config.Service) checker.ServiceStatus {
+	status := p.stateManager.ApplyThresholds(checker.Check(service), service)
+
+	// Save to storage if configured
+	if p.store != nil {
+		if err := p.store.SaveCheck(status); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Failed to save check to storage: %v\n", err)
+		}
+	}
+
+	// Record metrics if enabled
+	if p.cfg.Metrics.Enabled {
+		metrics.RecordCheck(status)
+	}
+
+	processNotifications(p.notifications, p.stateManager, status, service)
+
+	// Persist the state so a restart does not lose transitions
+	if p.store != nil {
+		if err := p.store.SaveState(p.stateManager.Snapshot(status.ID)); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Failed to save service state: %v\n", err)
+		}
+	}
+
+	return status
 }
 
 func runChecksAndGetStatus(cfg *config.Config, stateManager *StateManager, store storage.Storage) bool {
 	fmt.Printf("[%s] --- Running Checks ---\n", time.Now().Format("2006-01-02 15:04:05"))
-	allUp := true
 
-	for _, service := range cfg.Services {
-		status := checker.CheckService(service.Name, service.URL, service.Timeout)
+	statuses := runChecks(cfg, stateManager, store, func(status checker.ServiceStatus) {
 		fmt.Println(status)
+	})
 
-		if !status.IsUp {
-			allUp = false
-		}
+	fmt.Println("---------------------------------------")
+	return allUp(statuses)
+}
 
-		// Save to storage if configured
-		if store != nil {
-			if err := store.SaveCheck(status); err != nil {
-				log.Printf("ERROR: Failed to save check to storage: %v", err)
+// runChecks checks every service once through the pipeline run uses, and
+// returns the statuses in configuration order. Checks run concurrently on a
+// pool of SENTINEL_WORKERS workers; report is still called in configuration
+// order, for each service as soon as it and all services before it are done.
+func runChecks(cfg *config.Config, stateManager *StateManager, store storage.Storage, report func(checker.ServiceStatus)) []checker.ServiceStatus {
+	pipeline := newCheckPipeline(cfg, stateManager, store)
+	statuses := make([]checker.ServiceStatus, len(cfg.Services))
+	jobs := make(chan int)
+	done := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < min(getWorkerCount(), len(cfg.Services)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				statuses[i] = pipeline.process(cfg.Services[i])
+				done <- i
 			}
-		}
+		}()
+	}
 
-		processNotifications(cfg, stateManager, status, service)
+	go func() {
+		for i := range cfg.Services {
+			jobs <- i
+		}
+		close(jobs)
+		wg.Wait()
+		close(done)
+	}()
+
+	finished := make([]bool, len(statuses))
+	next := 0
+	for i := range done {
+		finished[i] = true
+		for ; next < len(statuses) && finished[next]; next++ {
+			if report != nil {
+				report(statuses[next])
+			}
+		}
 	}
 
-	fmt.Println("---------------------------------------")
-	return allUp
+	return statuses
+}
+
+// allUp reports whether every check succeeded
+func allUp(statuses []checker.ServiceStatus) bool {
+	for _, status := range statuses {
+		if !status.IsUp {
+			return false
+		}
+	}
+	return true
 }
 
 // contains checks if a string exists within a slice of strings.
@@ -301,4 +767,4 @@ func contains(s []string, e string) bool {
 		}
 	}
 	return false
-}
\ No newline at end of file
+}
@@ -1,5 +1,3 @@
-
-
 package cmd
 
 import (
@@ -11,7 +9,6 @@ import (
 	"syscall"
 	"time"
 
-	"github.com/0xReLogic/SENTINEL/checker"
 	"github.com/0xReLogic/SENTINEL/config"
 	"github.com/0xReLogic/SENTINEL/metrics"
 	"github.com/0xReLogic/SENTINEL/storage"
@@ -61,6 +58,17 @@ var runCmd = &cobra.Command{
 
 		stateManager := NewStateManager()
 
+		// Restore state from before the last restart so alerts and downtime carry over
+		if store != nil {
+			states, err := store.LoadStates()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: Failed to restore service state: %v\n", err)
+			} else {
+				stateManager.Restore(states)
+			}
+		}
+
+		pipeline := newCheckPipeline(cfg, stateManager, store)
 		workerCount := getWorkerCount()
 		jobQueue := make(chan config.Service, workerCount)
 
@@ -102,25 +110,11 @@ var runCmd = &cobra.Command{
 						if !ok {
 							return
 						}
-						status := checker.CheckService(service.Name, service.URL, service.Timeout)
-						
+						status := pipeline.process(service)
+
 						mu.Lock()
 						fmt.Println(status)
 						mu.Unlock()
-
-						// Save to storage if configured
-						if store != nil {
-							if err := store.SaveCheck(status); err != nil {
-								fmt.Fprintf(os.Stderr, "Warning: Failed to save check to storage: %v\n", err)
-							}
-						}
-
-						// Record metrics if enabled
-						if cfg.Metrics.Enabled {
-							metrics.RecordCheck(status)
-						}
-
-						processNotifications(cfg, stateManager, status, service)
 					}
 				}
 			}()
@@ -183,4 +177,4 @@ func getWorkerCount() int {
 	}
 	fmt.Fprintf(os.Stderr, msgInvalidWorkerCountEnv, envWorkerCount, value, defaultWorkerCount)
 	return defaultWorkerCount
-}
\ No newline at end of file
+}
@@ -28,8 +28,9 @@ var validateCmd = &cobra.Command{
 			os.Exit(exitConfigError)
 		}
 
-		// validate each service
+		// validate each service and notification channel
 		errors := validateServices(cfg.Services)
+		errors = append(errors, validateNotifications(cfg.Notifications)...)
 		if len(errors) > 0 {
 			fmt.Fprint(os.Stderr, msgValidationFailed)
 			for _, err := range errors {
@@ -43,7 +44,7 @@ var validateCmd = &cobra.Command{
 		fmt.Printf(fmtLoadedServicesValidation, len(cfg.Services))
 		fmt.Println(msgServicesConfigured)
 		for i, service := range cfg.Services {
-			fmt.Printf(fmtServiceListItem, i+1, service.Name, service.URL,
+			fmt.Printf(fmtServiceListItem, i+1, service.Name, service.Target(),
 				service.Interval, service.Timeout)
 		}
 		os.Exit(exitSuccess)
@@ -5,26 +5,187 @@ package config
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 	"time"
+	"unicode"
 
 	"gopkg.in/yaml.v3"
-	
 )
 
 // Default configuration values
 const (
 	DefaultInterval = 1 * time.Minute
 	DefaultTimeout  = 5 * time.Second
+	DefaultMethod   = "GET"
+
+	// DefaultThreshold is the number of consecutive results needed to change state
+	DefaultThreshold = 1
+
+	// DefaultRetryDelay is the wait before the first retry, doubled for every further retry
+	DefaultRetryDelay = 1 * time.Second
+
+	// MaxRetryDelay caps the doubling of the wait between retries
+	MaxRetryDelay = 30 * time.Second
+
+	// DefaultMaxBodySize caps how much of a response body is read for assertions
+	DefaultMaxBodySize = 1 << 20
 )
 
+// Service check types
+const (
+	TypeHTTP = "http"
+	TypeTCP  = "tcp"
+	TypeDNS  = "dns"
+)
 
+// DefaultRecordType is the DNS record type queried when none is configured
+const DefaultRecordType = "A"
 
 // Service represents a single service to be monitored
 type Service struct {
-	Name     string        `yaml:"name"`
-	URL      string        `yaml:"url"`
-	Interval time.Duration `yaml:"interval"`
-	Timeout  time.Duration `yaml:"timeout"`
+	// ID identifies the service in state, history and metrics. It defaults
+	// to a slug of the name, so set it to keep history when renaming.
+	ID       string            `yaml:"id"`
+	Name     string            `yaml:"name"`
+	Type     string            `yaml:"type"`
+	URL      string            `yaml:"url"`
+	Interval time.Duration     `yaml:"interval"`
+	Timeout  time.Duration     `yaml:"timeout"`
+	Method   string            `yaml:"method"`
+	Headers  map[string]string `yaml:"headers"`
+	Body     string            `yaml:"body"`
+	BodyFile string            `yaml:"body_file"`
+
+	ExpectedStatus StatusRules `yaml:"expected_status"`
+	Assertions     []Assertion `yaml:"assertions"`
+	MaxBodySize    int64       `yaml:"max_body_size"`
+
+	CertExpiryWarning time.Duration `yaml:"cert_expiry_warning"`
+
+	// Checks slower than DegradedThreshold report the service as DEGRADED
+	// instead of UP
+	DegradedThreshold time.Duration `yaml:"degraded_threshold"`
+
+	// Consecutive failed (or degraded) and successful checks required before
+	// the service state changes to DOWN (or DEGRADED) and back to UP
+	FailureThreshold int `yaml:"failure_threshold"`
+	SuccessThreshold int `yaml:"success_threshold"`
+
+	// Failed attempts are retried up to Retries times within one check,
+	// waiting RetryDelay before the first retry and doubling it after each,
+	// up to MaxRetryDelay
+	Retries    int           `yaml:"retries"`
+	RetryDelay time.Duration `yaml:"retry_delay"`
+
+	// Escalation names the escalation policy that alerts about outages of
+	// the service instead of the channels' notify_on down and recovery events
+	Escalation string `yaml:"escalation"`
+
+	// TCP checks connect to Host (host:port), optionally send a payload and
+	// expect a banner in the reply. DNS checks query Host as the record name.
+	Host   string `yaml:"host"`
+	Send   string `yaml:"send"`
+	Expect string `yaml:"expect"`
+
+	// DNS checks query RecordType against Resolver (host[:port], system
+	// resolver if empty) and assert on the returned answers
+	RecordType    string   `yaml:"record_type"`
+	Resolver      string   `yaml:"resolver"`
+	ExpectAnswers []string `yaml:"expect_answers"`
+	MinAnswers    int      `yaml:"min_answers"`
+}
+
+// Key returns the stable ID of the service, derived from the name if no id is set
+func (s Service) Key() string {
+	if s.ID != "" {
+		return s.ID
+	}
+	return Slugify(s.Name)
+}
+
+// RetryWait returns the wait before the retry with the given index, starting
+// at 0: RetryDelay doubled for every earlier retry, up to MaxRetryDelay. A
+// RetryDelay above MaxRetryDelay is used as is.
+func (s Service) RetryWait(retry int) time.Duration {
+	delay := s.RetryDelay
+	for i := 0; i < retry && delay < MaxRetryDelay; i++ {
+		delay = min(delay*2, MaxRetryDelay)
+	}
+	return delay
+}
+
+// MaxCheckDuration returns how long a check takes when every attempt runs
+// into the timeout, including the waits between retries
+func (s Service) MaxCheckDuration() time.Duration {
+	total := time.Duration(s.Retries+1) * s.Timeout
+	for retry := 0; retry < s.Retries; retry++ {
+		total += s.RetryWait(retry)
+	}
+	return total
+}
+
+// Slugify turns a service name into an ID: lower case letters and digits
+// separated by single dashes, e.g. "My API (EU)" becomes "my-api-eu"
+func Slugify(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		dash = true
+	}
+	return b.String()
+}
+
+// Target returns the address the service is checked against
+func (s Service) Target() string {
+	switch s.Type {
+	case TypeTCP:
+		return "tcp://" + s.Host
+	case TypeDNS:
+		// RFC 4501 style, e.g. dns://1.1.1.1/example.com?type=A
+		if s.Resolver != "" {
+			return "dns://" + s.Resolver + "/" + s.Host + "?type=" + s.RecordType
+		}
+		return "dns:" + s.Host + "?type=" + s.RecordType
+	}
+	return s.URL
+}
+
+// Assertion is a check run against the response body. Exactly one of the
+// fields must be set.
+type Assertion struct {
+	Contains    string `yaml:"contains"`
+	NotContains string `yaml:"not_contains"`
+	Regex       string `yaml:"regex"`
+	JSON        string `yaml:"json"`
+}
+
+// StatusRules lists the HTTP status codes that count as a successful check.
+// It can be written in YAML as a single value (401, "2xx") or as a list
+// ([200, "300-399"]).
+type StatusRules []string
+
+// UnmarshalYAML accepts both a scalar and a sequence of status rules
+func (r *StatusRules) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		*r = StatusRules{value.Value}
+		return nil
+	}
+
+	var rules []string
+	if err := value.Decode(&rules); err != nil {
+		return err
+	}
+	*r = rules
+	return nil
 }
 
 // Config represents the main configuration structure
@@ -40,17 +201,124 @@ type TelegramConfig struct {
 	BotToken string   `yaml:"bot_token"`
 	ChatID   string   `yaml:"chat_id"`
 	NotifyOn []string `yaml:"notify_on"`
+
+	Reminders `yaml:",inline"`
 }
 
 type DiscordConfig struct {
 	Enabled    bool     `yaml:"enabled"`
 	WebhookURL string   `yaml:"webhook_url"`
 	NotifyOn   []string `yaml:"notify_on"`
+
+	Reminders `yaml:",inline"`
+}
+
+type SlackConfig struct {
+	Enabled    bool     `yaml:"enabled"`
+	WebhookURL string   `yaml:"webhook_url"`
+	NotifyOn   []string `yaml:"notify_on"`
+
+	Reminders `yaml:",inline"`
+}
+
+// WebhookConfig sends events to any HTTP endpoint. The body is rendered from
+// the event with a text/template; with a Secret it is signed with
+// HMAC-SHA256 in SignatureHeader.
+type WebhookConfig struct {
+	Enabled         bool              `yaml:"enabled"`
+	URL             string            `yaml:"url"`
+	Method          string            `yaml:"method"`
+	Headers         map[string]string `yaml:"headers"`
+	Template        string            `yaml:"template"`
+	Secret          string            `yaml:"secret"`
+	SignatureHeader string            `yaml:"signature_header"`
+	NotifyOn        []string          `yaml:"notify_on"`
+
+	Reminders `yaml:",inline"`
+}
+
+// EmailConfig sends events by email over SMTP. TLS is "starttls" (the
+// default), "tls" for implicit TLS or "none"; Username enables PLAIN auth.
+type EmailConfig struct {
+	Enabled  bool     `yaml:"enabled"`
+	Host     string   `yaml:"host"`
+	Port     int      `yaml:"port"`
+	TLS      string   `yaml:"tls"`
+	Username string   `yaml:"username"`
+	Password string   `yaml:"password"`
+	From     string   `yaml:"from"`
+	To       []string `yaml:"to"`
+	NotifyOn []string `yaml:"notify_on"`
+
+	Reminders `yaml:",inline"`
+}
+
+// Reminders configures "still down" reminders of a notification channel.
+// While a service stays down a reminder is sent every RepeatInterval, at most
+// MaxReminders times per outage (0 means no limit). A zero RepeatInterval
+// disables reminders.
+type Reminders struct {
+	RepeatInterval time.Duration `yaml:"repeat_interval"`
+	MaxReminders   int           `yaml:"max_reminders"`
 }
 
 type NotificationConfig struct {
 	Telegram TelegramConfig `yaml:"telegram"`
 	Discord  DiscordConfig  `yaml:"discord"`
+	Slack    SlackConfig    `yaml:"slack"`
+	Webhook  WebhookConfig  `yaml:"webhook"`
+	Email    EmailConfig    `yaml:"email"`
+
+	EscalationPolicies []EscalationPolicy `yaml:"escalation_policies"`
+}
+
+// Notification channels that escalation steps can reference
+const (
+	ChannelTelegram = "telegram"
+	ChannelDiscord  = "discord"
+	ChannelSlack    = "slack"
+	ChannelWebhook  = "webhook"
+	ChannelEmail    = "email"
+)
+
+// EscalationPolicy alerts more channels the longer a service stays down.
+// Steps are ordered by their After delay.
+type EscalationPolicy struct {
+	Name  string           `yaml:"name"`
+	Steps []EscalationStep `yaml:"steps"`
+}
+
+// EscalationStep alerts Channels once a service has been down for After.
+// ChatID and WebhookURL send the step to another Telegram chat or Discord or
+// Slack webhook than the channel's own, e.g. a manager channel.
+type EscalationStep struct {
+	After      time.Duration `yaml:"after"`
+	Channels   []string      `yaml:"channels"`
+	ChatID     string        `yaml:"chat_id"`
+	WebhookURL string        `yaml:"webhook_url"`
+}
+
+// EscalationPolicy returns the escalation policy with the given name
+func (n NotificationConfig) EscalationPolicy(name string) (EscalationPolicy, bool) {
+	for _, policy := range n.EscalationPolicies {
+		if policy.Name == name {
+			return policy, true
+		}
+	}
+	return EscalationPolicy{}, false
+}
+
+// Apply returns the notification settings with the chat and webhook of the
+// step in place of the channels' own
+func (s EscalationStep) Apply(n NotificationConfig) NotificationConfig {
+	if s.ChatID != "" {
+		n.Telegram.ChatID = s.ChatID
+	}
+	if s.WebhookURL != "" {
+		n.Discord.WebhookURL = s.WebhookURL
+		n.Slack.WebhookURL = s.WebhookURL
+	}
+	return n
 }
 
 type StorageConfig struct {
@@ -64,6 +332,7 @@ type MetricsConfig struct {
 	Port    int    `yaml:"port"`
 	Path    string `yaml:"path"`
 }
+
 // LoadConfig reads the configuration file from the given path, expands any
 // environment variables, and unmarshals it into a Config struct.
 func LoadConfig(filePath string) (*Config, error) {
@@ -83,12 +352,36 @@ func LoadConfig(filePath string) (*Config, error) {
 	for i := range config.Services {
 		svc := &config.Services[i]
 
+		if svc.ID == "" {
+			svc.ID = Slugify(svc.Name)
+		}
+		if svc.Type == "" {
+			svc.Type = TypeHTTP
+		}
 		if svc.Interval == 0 {
 			svc.Interval = DefaultInterval
 		}
 		if svc.Timeout == 0 {
 			svc.Timeout = DefaultTimeout
 		}
+		if svc.Method == "" {
+			svc.Method = DefaultMethod
+		}
+		if svc.MaxBodySize == 0 {
+			svc.MaxBodySize = DefaultMaxBodySize
+		}
+		if svc.FailureThreshold == 0 {
+			svc.FailureThreshold = DefaultThreshold
+		}
+		if svc.SuccessThreshold == 0 {
+			svc.SuccessThreshold = DefaultThreshold
+		}
+		if svc.Retries > 0 && svc.RetryDelay == 0 {
+			svc.RetryDelay = DefaultRetryDelay
+		}
+		if svc.Type == TypeDNS && svc.RecordType == "" {
+			svc.RecordType = DefaultRecordType
+		}
 
 		// Validate
 		if svc.Interval < 0 {
@@ -97,7 +390,151 @@ func LoadConfig(filePath string) (*Config, error) {
 		if svc.Timeout < 0 {
 			return nil, fmt.Errorf("service '%s': timeout must be positive, got %v", svc.Name, svc.Timeout)
 		}
+		if svc.FailureThreshold < 0 {
+			return nil, fmt.Errorf("service '%s': failure_threshold must be positive, got %d", svc.Name, svc.FailureThreshold)
+		}
+		if svc.SuccessThreshold < 0 {
+			return nil, fmt.Errorf("service '%s': success_threshold must be positive, got %d", svc.Name, svc.SuccessThreshold)
+		}
+		if svc.Retries < 0 {
+			return nil, fmt.Errorf("service '%s': retries must not be negative, got %d", svc.Name, svc.Retries)
+		}
+		if svc.RetryDelay < 0 {
+			return nil, fmt.Errorf("service '%s': retry_delay must be positive, got %v", svc.Name, svc.RetryDelay)
+		}
+		if svc.DegradedThreshold < 0 {
+			return nil, fmt.Errorf("service '%s': degraded_threshold must be positive, got %v", svc.Name, svc.DegradedThreshold)
+		}
+		if svc.CertExpiryWarning < 0 {
+			return nil, fmt.Errorf("service '%s': cert_expiry_warning must be positive, got %v", svc.Name, svc.CertExpiryWarning)
+		}
+		if svc.MaxBodySize < 0 {
+			return nil, fmt.Errorf("service '%s': max_body_size must be positive, got %d", svc.Name, svc.MaxBodySize)
+		}
+
+		// Load the request body from a file, resolved relative to the config file
+		if svc.BodyFile != "" {
+			if svc.Body != "" {
+				return nil, fmt.Errorf("service '%s': body and body_file are mutually exclusive", svc.Name)
+			}
+			bodyPath := svc.BodyFile
+			if !filepath.IsAbs(bodyPath) {
+				bodyPath = filepath.Join(filepath.Dir(filePath), bodyPath)
+			}
+			body, err := os.ReadFile(bodyPath)
+			if err != nil {
+				return nil, fmt.Errorf("service '%s': error reading body_file: %w", svc.Name, err)
+			}
+			svc.Body = string(body)
+		}
+	}
+
+	if err := config.Notifications.Telegram.Reminders.validate("telegram"); err != nil {
+		return nil, err
+	}
+	if err := config.Notifications.Discord.Reminders.validate("discord"); err != nil {
+		return nil, err
+	}
+	if err := config.Notifications.Slack.Reminders.validate("slack"); err != nil {
+		return nil, err
+	}
+	if err := config.Notifications.Webhook.Reminders.validate("webhook"); err != nil {
+		return nil, err
+	}
+	if err := config.Notifications.Email.Reminders.validate("email"); err != nil {
+		return nil, err
+	}
+	if err := config.Notifications.validateEscalation(); err != nil {
+		return nil, err
+	}
+	for _, svc := range config.Services {
+		if _, ok := config.Notifications.EscalationPolicy(svc.Escalation); svc.Escalation != "" && !ok {
+			return nil, fmt.Errorf("service '%s': unknown escalation policy '%s'", svc.Name, svc.Escalation)
+		}
 	}
 
 	return &config, nil
 }
+
+// validateEscalation checks that escalation policies have unique names and
+// steps in order that only reference enabled channels
+func (n NotificationConfig) validateEscalation() error {
+	enabled := map[string]bool{
+		ChannelTelegram: n.Telegram.Enabled,
+		ChannelDiscord:  n.Discord.Enabled,
+		ChannelSlack:    n.Slack.Enabled,
+		ChannelWebhook:  n.Webhook.Enabled,
+		ChannelEmail:    n.Email.Enabled,
+	}
+	seen := make(map[string]bool)
+
+	for _, policy := range n.EscalationPolicies {
+		if policy.Name == "" {
+			return fmt.Errorf("notifications.escalation_policies: policy name is required")
+		}
+		if seen[policy.Name] {
+			return fmt.Errorf("escalation policy '%s': duplicate name", policy.Name)
+		}
+		seen[policy.Name] = true
+
+		if len(policy.Steps) == 0 {
+			return fmt.Errorf("escalation policy '%s': at least one step is required", policy.Name)
+		}
+		for i, step := range policy.Steps {
+			if step.After < 0 {
+				return fmt.Errorf("escalation policy '%s' step %d: after must be positive, got %v", policy.Name, i+1, step.After)
+			}
+			if i > 0 && step.After < policy.Steps[i-1].After {
+				return fmt.Errorf("escalation policy '%s' step %d: steps must be ordered by after", policy.Name, i+1)
+			}
+			if len(step.Channels) == 0 {
+				return fmt.Errorf("escalation policy '%s' step %d: at least one channel is required", policy.Name, i+1)
+			}
+			for _, channel := range step.Channels {
+				on, known := enabled[channel]
+				if !known {
+					return fmt.Errorf("escalation policy '%s' step %d: unknown channel '%s'", policy.Name, i+1, channel)
+				}
+				if !on {
+					return fmt.Errorf("escalation policy '%s' step %d: channel '%s' is not enabled", policy.Name, i+1, channel)
+				}
+			}
+			if err := step.validateOverrides(); err != nil {
+				return fmt.Errorf("escalation policy '%s' step %d: %w", policy.Name, i+1, err)
+			}
+		}
+	}
+	return nil
+}
+
+// validateOverrides checks that the chat and webhook of a step are used by
+// exactly the channels they replace the settings of
+func (s EscalationStep) validateOverrides() error {
+	uses := make(map[string]bool)
+	for _, channel := range s.Channels {
+		uses[channel] = true
+	}
+	if s.ChatID != "" && !uses[ChannelTelegram] {
+		return fmt.Errorf("chat_id requires the telegram channel")
+	}
+	if s.WebhookURL != "" {
+		if !uses[ChannelDiscord] && !uses[ChannelSlack] {
+			return fmt.Errorf("webhook_url requires the discord or slack channel")
+		}
+		if uses[ChannelDiscord] && uses[ChannelSlack] {
+			return fmt.Errorf("webhook_url is ambiguous with both the discord and slack channels")
+		}
+	}
+	return nil
+}
+
+// validate checks the reminder settings of a notification channel
+func (r Reminders) validate(channel string) error {
+	if r.RepeatInterval < 0 {
+		return fmt.Errorf("notifications.%s: repeat_interval must be positive, got %v", channel, r.RepeatInterval)
+	}
+	if r.MaxReminders < 0 {
+		return fmt.Errorf("notifications.%s: max_reminders must not be negative, got %d", channel, r.MaxReminders)
+	}
+	return nil
+}
@@ -1,8 +1,10 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
@@ -29,6 +31,7 @@ services:
   - name: "Test Service"
     url: "https://example.com"
   - name: "Another Service"
+    id: "another"
     url: "https://example.org"
     interval: 2m
     timeout: 39s
@@ -80,6 +83,62 @@ services:
 	if config.Services[1].URL != "https://example.org" {
 		t.Errorf("Expected service URL 'https://example.org', got '%s'", config.Services[1].URL)
 	}
+
+	if config.Services[0].ID != "test-service" {
+		t.Errorf("Expected id derived from name 'test-service', got '%s'", config.Services[0].ID)
+	}
+
+	if config.Services[1].ID != "another" {
+		t.Errorf("Expected configured id 'another', got '%s'", config.Services[1].ID)
+	}
+}
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Google", "google"},
+		{"My API (EU)", "my-api-eu"},
+		{"  spaced   out  ", "spaced-out"},
+		{"api.example.com/health", "api-example-com-health"},
+		{"Café Über", "café-über"},
+		{"!!!", ""},
+	}
+
+	for _, tt := range tests {
+		if got := Slugify(tt.name); got != tt.want {
+			t.Errorf("Slugify(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestServiceKey(t *testing.T) {
+	if got := (Service{Name: "My API"}).Key(); got != "my-api" {
+		t.Errorf("Expected key derived from name 'my-api', got '%s'", got)
+	}
+	if got := (Service{ID: "api_v2", Name: "My API"}).Key(); got != "api_v2" {
+		t.Errorf("Expected configured id 'api_v2', got '%s'", got)
+	}
+}
+
+func TestServiceRetryWait(t *testing.T) {
+	service := Service{Timeout: 5 * time.Second, Retries: 8, RetryDelay: 5 * time.Second}
+	want := []time.Duration{5 * time.Second, 10 * time.Second, 20 * time.Second, 30 * time.Second, 30 * time.Second}
+	for retry, delay := range want {
+		if got := service.RetryWait(retry); got != delay {
+			t.Errorf("Expected a wait of %v before retry %d, got %v", delay, retry, got)
+		}
+	}
+	// Nine timeouts and waits of 5s, 10s, 20s and five times 30s
+	if got := service.MaxCheckDuration(); got != 45*time.Second+185*time.Second {
+		t.Errorf("Expected a worst case of 3m50s, got %v", got)
+	}
+
+	service.RetryDelay = time.Minute
+	if got := service.RetryWait(3); got != time.Minute {
+		t.Errorf("Expected a configured delay above the cap to be kept, got %v", got)
+	}
 }
 
 func TestLoadConfigInvalidPath(t *testing.T) {
@@ -163,6 +222,251 @@ services:
 	}
 }
 
+func TestLoadConfigReminders(t *testing.T) {
+	tempDir := t.TempDir()
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			content: `
+notifications:
+  telegram:
+    repeat_interval: 1h
+    max_reminders: 3
+  discord:
+    repeat_interval: 30m
+`,
+		},
+		{
+			name: "negative repeat_interval",
+			content: `
+notifications:
+  discord:
+    repeat_interval: -1m
+`,
+			wantErr: true,
+		},
+		{
+			name: "negative slack repeat_interval",
+			content: `
+notifications:
+  slack:
+    repeat_interval: -1m
+`,
+			wantErr: true,
+		},
+		{
+			name: "negative email max_reminders",
+			content: `
+notifications:
+  email:
+    repeat_interval: 1h
+    max_reminders: -1
+`,
+			wantErr: true,
+		},
+		{
+			name: "negative max_reminders",
+			content: `
+notifications:
+  telegram:
+    repeat_interval: 1h
+    max_reminders: -1
+`,
+			wantErr: true,
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(tempDir, fmt.Sprintf("reminders-%d.yaml", i))
+			if err := os.WriteFile(configPath, []byte(tt.content), 0644); err != nil {
+				t.Fatalf(errMsgWriteConfig, err)
+			}
+
+			cfg, err := LoadConfig(configPath)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("Expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadConfig failed: %v", err)
+			}
+
+			telegram := cfg.Notifications.Telegram.Reminders
+			if telegram.RepeatInterval != time.Hour || telegram.MaxReminders != 3 {
+				t.Errorf("Expected telegram reminders every 1h, at most 3, got %+v", telegram)
+			}
+			discord := cfg.Notifications.Discord.Reminders
+			if discord.RepeatInterval != 30*time.Minute || discord.MaxReminders != 0 {
+				t.Errorf("Expected unlimited discord reminders every 30m, got %+v", discord)
+			}
+		})
+	}
+}
+
+func TestLoadConfigEscalation(t *testing.T) {
+	tempDir := t.TempDir()
+	channels := `
+notifications:
+  telegram:
+    enabled: true
+  discord:
+    enabled: true
+`
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{
+			name: "valid",
+			content: channels + `
+  escalation_policies:
+    - name: on-call
+      steps:
+        - channels: [discord]
+        - after: 15m
+          channels: [telegram]
+        - after: 1h
+          channels: [telegram]
+          chat_id: "managers"
+services:
+  - name: "API"
+    url: "https://api.example.com"
+    escalation: on-call
+`,
+		},
+		{
+			name: "unknown policy",
+			content: channels + `
+services:
+  - name: "API"
+    url: "https://api.example.com"
+    escalation: missing
+`,
+			wantErr: "unknown escalation policy",
+		},
+		{
+			name: "unordered steps",
+			content: channels + `
+  escalation_policies:
+    - name: on-call
+      steps:
+        - after: 1h
+          channels: [telegram]
+        - after: 15m
+          channels: [discord]
+`,
+			wantErr: "ordered",
+		},
+		{
+			name: "unknown channel",
+			content: channels + `
+  escalation_policies:
+    - name: on-call
+      steps:
+        - channels: [pager]
+`,
+			wantErr: "unknown channel",
+		},
+		{
+			name: "disabled channel",
+			content: `
+notifications:
+  escalation_policies:
+    - name: on-call
+      steps:
+        - channels: [discord]
+`,
+			wantErr: "not enabled",
+		},
+		{
+			name: "chat_id without telegram",
+			content: channels + `
+  escalation_policies:
+    - name: on-call
+      steps:
+        - channels: [discord]
+          chat_id: "managers"
+`,
+			wantErr: "chat_id requires the telegram channel",
+		},
+		{
+			name: "webhook_url without discord or slack",
+			content: channels + `
+  escalation_policies:
+    - name: on-call
+      steps:
+        - channels: [telegram]
+          webhook_url: "https://hooks.example.com/managers"
+`,
+			wantErr: "webhook_url requires the discord or slack channel",
+		},
+		{
+			name: "webhook_url for discord and slack",
+			content: channels + `
+  slack:
+    enabled: true
+  escalation_policies:
+    - name: on-call
+      steps:
+        - channels: [discord, slack]
+          webhook_url: "https://hooks.example.com/managers"
+`,
+			wantErr: "ambiguous",
+		},
+		{
+			name: "duplicate name",
+			content: channels + `
+  escalation_policies:
+    - name: on-call
+      steps:
+        - channels: [discord]
+    - name: on-call
+      steps:
+        - channels: [telegram]
+`,
+			wantErr: "duplicate",
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(tempDir, fmt.Sprintf("escalation-%d.yaml", i))
+			if err := os.WriteFile(configPath, []byte(tt.content), 0644); err != nil {
+				t.Fatalf(errMsgWriteConfig, err)
+			}
+
+			cfg, err := LoadConfig(configPath)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadConfig failed: %v", err)
+			}
+
+			policy, ok := cfg.Notifications.EscalationPolicy(cfg.Services[0].Escalation)
+			if !ok {
+				t.Fatalf("Expected escalation policy %q", cfg.Services[0].Escalation)
+			}
+			if len(policy.Steps) != 3 || policy.Steps[1].After != 15*time.Minute || policy.Steps[2].ChatID != "managers" {
+				t.Errorf("Unexpected escalation steps: %+v", policy.Steps)
+			}
+		})
+	}
+}
+
 func TestLoadConfigInvalidDuration(t *testing.T) {
 	// Create a temporary directory for test files
 	tempDir, err := os.MkdirTemp("", testDirPrefix)
@@ -233,6 +537,129 @@ services:
 	}
 }
 
+func TestLoadConfigRequestOptions(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", testDirPrefix)
+	if err != nil {
+		t.Fatalf(errMsgCreateTempDir, err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	if err := os.WriteFile(filepath.Join(tempDir, "rpc.json"), []byte(`{"method":"health"}`), 0644); err != nil {
+		t.Fatalf("Failed to write body file: %v", err)
+	}
+
+	configPath := filepath.Join(tempDir, "request-options.yaml")
+	configContent := `
+services:
+  - name: "Plain"
+    url: "https://example.com"
+  - name: "RPC"
+    url: "https://rpc.example.com"
+    method: POST
+    headers:
+      Authorization: "Bearer token"
+    body_file: rpc.json
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf(errMsgWriteConfig, err)
+	}
+
+	cfg, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+
+	if cfg.Services[0].Type != TypeHTTP {
+		t.Errorf("Expected default type %s, got %s", TypeHTTP, cfg.Services[0].Type)
+	}
+
+	if cfg.Services[0].FailureThreshold != DefaultThreshold || cfg.Services[0].SuccessThreshold != DefaultThreshold {
+		t.Errorf("Expected default thresholds %d, got %d/%d", DefaultThreshold, cfg.Services[0].FailureThreshold, cfg.Services[0].SuccessThreshold)
+	}
+
+	if cfg.Services[0].Method != DefaultMethod {
+		t.Errorf("Expected default method %s, got %s", DefaultMethod, cfg.Services[0].Method)
+	}
+
+	if cfg.Services[1].Method != "POST" {
+		t.Errorf("Expected method POST, got %s", cfg.Services[1].Method)
+	}
+
+	if cfg.Services[1].Headers["Authorization"] != "Bearer token" {
+		t.Errorf("Expected Authorization header 'Bearer token', got '%s'", cfg.Services[1].Headers["Authorization"])
+	}
+
+	if cfg.Services[1].Body != `{"method":"health"}` {
+		t.Errorf("Expected body loaded from body_file, got '%s'", cfg.Services[1].Body)
+	}
+}
+
+func TestLoadConfigBodyAndBodyFile(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", testDirPrefix)
+	if err != nil {
+		t.Fatalf(errMsgCreateTempDir, err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	configPath := filepath.Join(tempDir, "body-conflict.yaml")
+	configContent := `
+services:
+  - name: "RPC"
+    url: "https://rpc.example.com"
+    body: "{}"
+    body_file: rpc.json
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf(errMsgWriteConfig, err)
+	}
+
+	if _, err := LoadConfig(configPath); err == nil {
+		t.Fatal("Expected error when both body and body_file are set, got nil")
+	}
+}
+
+func TestLoadConfigExpectedStatus(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", testDirPrefix)
+	if err != nil {
+		t.Fatalf(errMsgCreateTempDir, err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	configPath := filepath.Join(tempDir, "expected-status.yaml")
+	configContent := `
+services:
+  - name: "Auth Wall"
+    url: "https://example.com/private"
+    expected_status: 401
+  - name: "API"
+    url: "https://example.com/api"
+    expected_status: [2xx, "300-302", 418]
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf(errMsgWriteConfig, err)
+	}
+
+	cfg, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+
+	if len(cfg.Services[0].ExpectedStatus) != 1 || cfg.Services[0].ExpectedStatus[0] != "401" {
+		t.Errorf("Expected expected_status [401], got %v", cfg.Services[0].ExpectedStatus)
+	}
+
+	want := []string{"2xx", "300-302", "418"}
+	got := cfg.Services[1].ExpectedStatus
+	if len(got) != len(want) {
+		t.Fatalf("Expected expected_status %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Expected expected_status %v, got %v", want, got)
+		}
+	}
+}
+
 func TestEmptyConfig(t *testing.T) {
 	// Create a temporary directory for test files
 	tempDir, err := os.MkdirTemp("", testDirPrefix)
@@ -2,6 +2,9 @@
 package metrics
 
 import (
+	"strings"
+	"time"
+
 	"github.com/0xReLogic/SENTINEL/checker"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
@@ -15,7 +18,17 @@ var (
 			Name:      "service_up",
 			Help:      "Service is up (1) or down (0)",
 		},
-		[]string{"service", "url"},
+		[]string{"id", "service", "url"},
+	)
+
+	// ServiceState is 1 for the current state of a service (up, degraded or down) and 0 for the others
+	ServiceState = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "sentinel",
+			Name:      "service_state",
+			Help:      "Current service state (1 for the active state label, 0 otherwise)",
+		},
+		[]string{"id", "service", "url", "state"},
 	)
 
 	// ResponseTime tracks HTTP response time in seconds
@@ -26,7 +39,7 @@ var (
 			Help:      "HTTP response time in seconds",
 			Buckets:   []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
 		},
-		[]string{"service", "url"},
+		[]string{"id", "service", "url"},
 	)
 
 	// ChecksTotal counts total number of checks performed
@@ -36,7 +49,7 @@ var (
 			Name:      "checks_total",
 			Help:      "Total number of checks performed",
 		},
-		[]string{"service", "status"},
+		[]string{"id", "service", "status"},
 	)
 
 	// HTTPStatusTotal counts HTTP status codes received
@@ -46,7 +59,17 @@ var (
 			Name:      "http_status_total",
 			Help:      "HTTP status codes received",
 		},
-		[]string{"service", "code"},
+		[]string{"id", "service", "code"},
+	)
+
+	// TLSCertExpiry tracks the time left until the service TLS certificate expires
+	TLSCertExpiry = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "sentinel",
+			Name:      "tls_cert_expiry_seconds",
+			Help:      "Seconds until the TLS certificate expires (negative if expired)",
+		},
+		[]string{"id", "service", "url"},
 	)
 )
 
@@ -57,21 +80,39 @@ func RecordCheck(status checker.ServiceStatus) {
 	if status.IsUp {
 		upValue = 1.0
 	}
-	ServiceUp.WithLabelValues(status.Name, status.URL).Set(upValue)
+	ServiceUp.WithLabelValues(status.ID, status.Name, status.URL).Set(upValue)
+
+	// Update the tri-state gauge, preferring the confirmed state over the raw result
+	state := status.State
+	if state == "" {
+		state = status.Result()
+	}
+	for _, s := range []string{checker.StateUp, checker.StateDegraded, checker.StateDown} {
+		value := 0.0
+		if s == state {
+			value = 1.0
+		}
+		ServiceState.WithLabelValues(status.ID, status.Name, status.URL, strings.ToLower(s)).Set(value)
+	}
 
 	// Record response time
-	ResponseTime.WithLabelValues(status.Name, status.URL).Observe(status.ResponseTime.Seconds())
+	ResponseTime.WithLabelValues(status.ID, status.Name, status.URL).Observe(status.ResponseTime.Seconds())
 
 	// Increment check counter
 	checkStatus := "failure"
 	if status.IsUp {
 		checkStatus = "success"
 	}
-	ChecksTotal.WithLabelValues(status.Name, checkStatus).Inc()
+	ChecksTotal.WithLabelValues(status.ID, status.Name, checkStatus).Inc()
 
 	// Record HTTP status code (only if we got a response)
 	if status.StatusCode > 0 {
-		HTTPStatusTotal.WithLabelValues(status.Name, statusCodeToString(status.StatusCode)).Inc()
+		HTTPStatusTotal.WithLabelValues(status.ID, status.Name, statusCodeToString(status.StatusCode)).Inc()
+	}
+
+	// Record certificate expiry (only for HTTPS responses)
+	if status.TLS != nil {
+		TLSCertExpiry.WithLabelValues(status.ID, status.Name, status.URL).Set(time.Until(status.TLS.NotAfter).Seconds())
 	}
 }
 
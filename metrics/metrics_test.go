@@ -9,12 +9,14 @@ import (
 )
 
 const (
+	testServiceID   = "test-service"
 	testServiceName = "Test Service"
 	testServiceURL  = "https://example.com"
 )
 
 func TestRecordCheckSuccess(t *testing.T) {
 	status := checker.ServiceStatus{
+		ID:           testServiceID,
 		Name:         testServiceName,
 		URL:          testServiceURL,
 		IsUp:         true,
@@ -25,7 +27,7 @@ func TestRecordCheckSuccess(t *testing.T) {
 	RecordCheck(status)
 
 	// Verify service_up gauge is 1
-	value := testutil.ToFloat64(ServiceUp.WithLabelValues(testServiceName, testServiceURL))
+	value := testutil.ToFloat64(ServiceUp.WithLabelValues(testServiceID, testServiceName, testServiceURL))
 	if value != 1.0 {
 		t.Errorf("Expected service_up to be 1, got %f", value)
 	}
@@ -33,6 +35,7 @@ func TestRecordCheckSuccess(t *testing.T) {
 
 func TestRecordCheckFailure(t *testing.T) {
 	status := checker.ServiceStatus{
+		ID:           "failed-service",
 		Name:         "Failed Service",
 		URL:          "https://failed.example.com",
 		IsUp:         false,
@@ -43,12 +46,53 @@ func TestRecordCheckFailure(t *testing.T) {
 	RecordCheck(status)
 
 	// Verify service_up gauge is 0
-	value := testutil.ToFloat64(ServiceUp.WithLabelValues("Failed Service", "https://failed.example.com"))
+	value := testutil.ToFloat64(ServiceUp.WithLabelValues("failed-service", "Failed Service", "https://failed.example.com"))
 	if value != 0.0 {
 		t.Errorf("Expected service_up to be 0, got %f", value)
 	}
 }
 
+func TestRecordCheckTLSCertExpiry(t *testing.T) {
+	status := checker.ServiceStatus{
+		ID:           "secure-service",
+		Name:         "Secure Service",
+		URL:          "https://secure.example.com",
+		IsUp:         true,
+		ResponseTime: 100 * time.Millisecond,
+		StatusCode:   200,
+		TLS:          &checker.CertificateInfo{NotAfter: time.Now().Add(48 * time.Hour)},
+	}
+
+	RecordCheck(status)
+
+	value := testutil.ToFloat64(TLSCertExpiry.WithLabelValues("secure-service", "Secure Service", "https://secure.example.com"))
+	if value < (47*time.Hour).Seconds() || value > (48*time.Hour).Seconds() {
+		t.Errorf("Expected tls_cert_expiry_seconds close to 48h, got %f", value)
+	}
+}
+
+func TestRecordCheckDegraded(t *testing.T) {
+	status := checker.ServiceStatus{
+		ID:           "slow-service",
+		Name:         "Slow Service",
+		URL:          "https://slow.example.com",
+		IsUp:         true,
+		Degraded:     true,
+		ResponseTime: 3 * time.Second,
+		StatusCode:   200,
+	}
+
+	RecordCheck(status)
+
+	want := map[string]float64{"up": 0, "degraded": 1, "down": 0}
+	for state, expected := range want {
+		value := testutil.ToFloat64(ServiceState.WithLabelValues("slow-service", "Slow Service", "https://slow.example.com", state))
+		if value != expected {
+			t.Errorf("Expected service_state{state=%q} = %f, got %f", state, expected, value)
+		}
+	}
+}
+
 func TestStatusCodeToString(t *testing.T) {
 	tests := []struct {
 		code     int
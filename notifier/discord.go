@@ -7,13 +7,16 @@ import (
 	"io"
 	"net/http"
 	"time"
+
+	"github.com/0xReLogic/SENTINEL/config"
 )
 
 // Discord embed colors
 const (
 	ColorRed    = 15158332 // #E74C3C - DOWN status
 	ColorGreen  = 3066993  // #2ECC71 - RECOVERY status
-	ColorOrange = 15105570 // #E67E22 - Degraded (future use)
+	ColorOrange = 15105570 // #E67E22 - DEGRADED status
+	ColorYellow = 15844367 // #F1C40F - Certificate expiring
 )
 
 // DiscordEmbed represents a Discord embed object
@@ -37,6 +40,52 @@ type DiscordWebhookPayload struct {
 	Embeds   []DiscordEmbed `json:"embeds"`
 }
 
+func init() {
+	Register(config.ChannelDiscord, func(cfg config.NotificationConfig) (Channel, bool) {
+		return Channel{
+			Notifier:  NewDiscordNotifier(cfg.Discord),
+			NotifyOn:  cfg.Discord.NotifyOn,
+			Reminders: cfg.Discord.Reminders,
+		}, cfg.Discord.Enabled
+	})
+}
+
+// DiscordNotifier sends events as embeds to a Discord webhook
+type DiscordNotifier struct {
+	WebhookURL string
+}
+
+// NewDiscordNotifier creates a notifier for the webhook of a Discord configuration
+func NewDiscordNotifier(cfg config.DiscordConfig) *DiscordNotifier {
+	return &DiscordNotifier{WebhookURL: cfg.WebhookURL}
+}
+
+// Notify formats the event and posts it to the webhook
+func (n *DiscordNotifier) Notify(event Event) error {
+	embed, err := FormatDiscordEmbed(event)
+	if err != nil {
+		return err
+	}
+	return SendDiscordNotification(n.WebhookURL, "", embed)
+}
+
+// FormatDiscordEmbed formats an event as a Discord embed
+func FormatDiscordEmbed(event Event) (DiscordEmbed, error) {
+	switch event.Type {
+	case EventDown:
+		return FormatDownEmbed(event.ServiceName, event.URL, event.Error, event.Time), nil
+	case EventStillDown:
+		return FormatStillDownEmbed(event.ServiceName, event.URL, event.Error, event.Downtime, event.Time), nil
+	case EventRecovery:
+		return FormatRecoveryEmbed(event.ServiceName, event.URL, event.Downtime, event.Time), nil
+	case EventDegraded:
+		return FormatDegradedEmbed(event.ServiceName, event.URL, event.ResponseTime, event.Threshold, event.Time), nil
+	case EventCertExpiring:
+		return FormatCertExpiryEmbed(event.ServiceName, event.URL, event.CertExpiry, event.CertIssuer, event.Time), nil
+	}
+	return DiscordEmbed{}, fmt.Errorf("unsupported event type %q", event.Type)
+}
+
 // SendDiscordNotification sends a message to Discord using webhook URL
 func SendDiscordNotification(webhookURL, message string, embed DiscordEmbed) error {
 	payload := DiscordWebhookPayload{
@@ -84,6 +133,21 @@ func FormatDownEmbed(name, url, errorMsg string, checkTime time.Time) DiscordEmb
 	}
 }
 
+// FormatStillDownEmbed creates a Discord embed reminding that a service is still DOWN
+func FormatStillDownEmbed(name, url, errorMsg string, downtime time.Duration, checkTime time.Time) DiscordEmbed {
+	return DiscordEmbed{
+		Title: "🔴 Service STILL DOWN",
+		Color: ColorRed,
+		Fields: []DiscordField{
+			{Name: "Service", Value: name, Inline: true},
+			{Name: "URL", Value: url, Inline: true},
+			{Name: "Down For", Value: formatDowntime(downtime), Inline: true},
+			{Name: "Error", Value: errorMsg, Inline: false},
+		},
+		Timestamp: checkTime.Format(time.RFC3339),
+	}
+}
+
 // FormatRecoveryEmbed creates a Discord embed for service RECOVERY notification
 func FormatRecoveryEmbed(name, url string, downtime time.Duration, recoveryTime time.Time) DiscordEmbed {
 	return DiscordEmbed{
@@ -97,3 +161,32 @@ func FormatRecoveryEmbed(name, url string, downtime time.Duration, recoveryTime
 		Timestamp: recoveryTime.Format(time.RFC3339),
 	}
 }
+
+// FormatDegradedEmbed creates a Discord embed for service DEGRADED notification
+func FormatDegradedEmbed(name, url string, responseTime, threshold time.Duration, checkTime time.Time) DiscordEmbed {
+	return DiscordEmbed{
+		Title: "🟠 Service DEGRADED",
+		Color: ColorOrange,
+		Fields: []DiscordField{
+			{Name: "Service", Value: name, Inline: true},
+			{Name: "URL", Value: url, Inline: true},
+			{Name: "Response Time", Value: formatSlowResponse(responseTime, threshold), Inline: false},
+		},
+		Timestamp: checkTime.Format(time.RFC3339),
+	}
+}
+
+// FormatCertExpiryEmbed creates a Discord embed for a certificate expiry warning
+func FormatCertExpiryEmbed(name, url string, notAfter time.Time, issuer string, checkTime time.Time) DiscordEmbed {
+	return DiscordEmbed{
+		Title: "🟡 Certificate EXPIRING",
+		Color: ColorYellow,
+		Fields: []DiscordField{
+			{Name: "Service", Value: name, Inline: true},
+			{Name: "URL", Value: url, Inline: true},
+			{Name: "Expires", Value: formatExpiry(notAfter, checkTime), Inline: false},
+			{Name: "Issuer", Value: issuer, Inline: false},
+		},
+		Timestamp: checkTime.Format(time.RFC3339),
+	}
+}
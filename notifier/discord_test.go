@@ -72,6 +72,69 @@ func TestFormatRecoveryEmbed(t *testing.T) {
 	}
 }
 
+func TestFormatCertExpiryEmbed(t *testing.T) {
+	checkTime := time.Date(2025, 10, 11, 22, 30, 0, 0, time.UTC)
+	notAfter := checkTime.Add(-time.Hour)
+
+	embed := FormatCertExpiryEmbed(testServiceName, testServiceURL, notAfter, "CN=Test CA", checkTime)
+
+	if embed.Title != "🟡 Certificate EXPIRING" {
+		t.Errorf("Expected title '🟡 Certificate EXPIRING', got '%s'", embed.Title)
+	}
+	if embed.Color != ColorYellow {
+		t.Errorf("Expected color %d, got %d", ColorYellow, embed.Color)
+	}
+	if len(embed.Fields) != 4 {
+		t.Fatalf("Expected 4 fields, got %d", len(embed.Fields))
+	}
+
+	assertEmbedField(t, embed.Fields[0], "Service", testServiceName)
+	assertEmbedField(t, embed.Fields[1], "URL", testServiceURL)
+	assertEmbedField(t, embed.Fields[2], "Expires", "2025-10-11 21:30:00 (expired)")
+	assertEmbedField(t, embed.Fields[3], "Issuer", "CN=Test CA")
+}
+
+func TestFormatStillDownEmbed(t *testing.T) {
+	checkTime := time.Date(2025, 10, 11, 22, 30, 0, 0, time.UTC)
+
+	embed := FormatStillDownEmbed(testServiceName, testServiceURL, "connection refused", 3*time.Hour+12*time.Minute, checkTime)
+
+	if embed.Title != "🔴 Service STILL DOWN" {
+		t.Errorf("Expected title '🔴 Service STILL DOWN', got '%s'", embed.Title)
+	}
+	if embed.Color != ColorRed {
+		t.Errorf("Expected color %d, got %d", ColorRed, embed.Color)
+	}
+	if len(embed.Fields) != 4 {
+		t.Fatalf("Expected 4 fields, got %d", len(embed.Fields))
+	}
+
+	assertEmbedField(t, embed.Fields[0], "Service", testServiceName)
+	assertEmbedField(t, embed.Fields[1], "URL", testServiceURL)
+	assertEmbedField(t, embed.Fields[2], "Down For", "3h12m")
+	assertEmbedField(t, embed.Fields[3], "Error", "connection refused")
+}
+
+func TestFormatDegradedEmbed(t *testing.T) {
+	checkTime := time.Date(2025, 10, 11, 22, 30, 0, 0, time.UTC)
+
+	embed := FormatDegradedEmbed(testServiceName, testServiceURL, 2500*time.Millisecond, time.Second, checkTime)
+
+	if embed.Title != "🟠 Service DEGRADED" {
+		t.Errorf("Expected title '🟠 Service DEGRADED', got '%s'", embed.Title)
+	}
+	if embed.Color != ColorOrange {
+		t.Errorf("Expected color %d, got %d", ColorOrange, embed.Color)
+	}
+	if len(embed.Fields) != 3 {
+		t.Fatalf("Expected 3 fields, got %d", len(embed.Fields))
+	}
+
+	assertEmbedField(t, embed.Fields[0], "Service", testServiceName)
+	assertEmbedField(t, embed.Fields[1], "URL", testServiceURL)
+	assertEmbedField(t, embed.Fields[2], "Response Time", "2500 ms (threshold 1000 ms)")
+}
+
 func TestSendDiscordNotificationSuccess(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != "POST" {
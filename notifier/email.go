@@ -0,0 +1,359 @@
+package notifier
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"html/template"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/0xReLogic/SENTINEL/config"
+)
+
+// Email TLS modes
+const (
+	EmailTLSStartTLS = "starttls"
+	EmailTLSImplicit = "tls"
+	EmailTLSNone     = "none"
+)
+
+// emailTimeout bounds a whole SMTP session, from dialing to QUIT
+const emailTimeout = 30 * time.Second
+
+// EmailMessage is an event formatted as an email
+type EmailMessage struct {
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// emailField is a labelled value of an email body
+type emailField struct {
+	Name  string
+	Value string
+}
+
+// emailHTML is the HTML body of every email
+var emailHTML = template.Must(template.New("email").Parse(`<!DOCTYPE html>
+<html>
+<body style="font-family: sans-serif; color: #333333;">
+<h2 style="color: {{.Color}};">{{.Title}}</h2>
+<table style="border-collapse: collapse;">
+{{- range .Fields}}
+<tr><td style="padding: 4px 16px 4px 0; font-weight: bold; vertical-align: top;">{{.Name}}</td><td style="padding: 4px 0;">{{.Value}}</td></tr>
+{{- end}}
+</table>
+<p style="color: #888888; font-size: 12px;">Sent by SENTINEL</p>
+</body>
+</html>
+`))
+
+func init() {
+	Register(config.ChannelEmail, func(cfg config.NotificationConfig) (Channel, bool) {
+		return Channel{
+			Notifier:  NewEmailNotifier(cfg.Email),
+			NotifyOn:  cfg.Email.NotifyOn,
+			Reminders: cfg.Email.Reminders,
+		}, cfg.Email.Enabled
+	})
+}
+
+// EmailNotifier sends events as multipart plain-text and HTML emails over SMTP
+type EmailNotifier struct {
+	Host     string
+	Port     int
+	TLS      string
+	Username string
+	Password string
+	From     string
+	To       []string
+
+	tlsConfig *tls.Config // overrides the TLS settings in tests
+}
+
+// NewEmailNotifier creates a notifier from an email configuration. Without a
+// port the standard port of the TLS mode is used: 587, 465 or 25.
+func NewEmailNotifier(cfg config.EmailConfig) *EmailNotifier {
+	n := &EmailNotifier{
+		Host:     cfg.Host,
+		Port:     cfg.Port,
+		TLS:      strings.ToLower(cfg.TLS),
+		Username: cfg.Username,
+		Password: cfg.Password,
+		From:     cfg.From,
+		To:       cfg.To,
+	}
+	if n.TLS == "" {
+		n.TLS = EmailTLSStartTLS
+	}
+	if n.Port == 0 {
+		switch n.TLS {
+		case EmailTLSImplicit:
+			n.Port = 465
+		case EmailTLSNone:
+			n.Port = 25
+		default:
+			n.Port = 587
+		}
+	}
+	return n
+}
+
+// Validate checks the server, TLS mode and addresses of the email settings
+func (n *EmailNotifier) Validate() []error {
+	var errors []error
+	if n.Host == "" {
+		errors = append(errors, fmt.Errorf("host is required"))
+	}
+	if n.Port < 1 || n.Port > 65535 {
+		errors = append(errors, fmt.Errorf("invalid port %d", n.Port))
+	}
+	switch n.TLS {
+	case EmailTLSStartTLS, EmailTLSImplicit, EmailTLSNone:
+	default:
+		errors = append(errors, fmt.Errorf("unsupported tls '%s', expected starttls, tls or none", n.TLS))
+	}
+	if n.From == "" {
+		errors = append(errors, fmt.Errorf("from is required"))
+	} else if _, err := mail.ParseAddress(n.From); err != nil {
+		errors = append(errors, fmt.Errorf("invalid from address '%s'", n.From))
+	}
+	if len(n.To) == 0 {
+		errors = append(errors, fmt.Errorf("at least one to address is required"))
+	}
+	for _, to := range n.To {
+		if _, err := mail.ParseAddress(to); err != nil {
+			errors = append(errors, fmt.Errorf("invalid to address '%s'", to))
+		}
+	}
+	return errors
+}
+
+// Notify formats the event and emails it to every recipient
+func (n *EmailNotifier) Notify(event Event) error {
+	message, err := FormatEmail(event)
+	if err != nil {
+		return err
+	}
+	from, err := mail.ParseAddress(n.From)
+	if err != nil {
+		return fmt.Errorf("invalid from address: %w", err)
+	}
+	var to []*mail.Address
+	for _, address := range n.To {
+		parsed, err := mail.ParseAddress(address)
+		if err != nil {
+			return fmt.Errorf("invalid to address: %w", err)
+		}
+		to = append(to, parsed)
+	}
+
+	body, err := buildEmail(from, to, message, event.Time)
+	if err != nil {
+		return err
+	}
+	return n.send(from, to, body)
+}
+
+// send delivers a message in a single SMTP session
+func (n *EmailNotifier) send(from *mail.Address, to []*mail.Address, body []byte) error {
+	addr := net.JoinHostPort(n.Host, strconv.Itoa(n.Port))
+	tlsConfig := n.tlsConfig
+	if tlsConfig == nil {
+		tlsConfig = &tls.Config{ServerName: n.Host}
+	}
+
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+	var conn net.Conn
+	var err error
+	if n.TLS == EmailTLSImplicit {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, tlsConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to connect to SMTP server: %w", err)
+	}
+	conn.SetDeadline(time.Now().Add(emailTimeout))
+
+	client, err := smtp.NewClient(conn, n.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to start SMTP session: %w", err)
+	}
+	defer client.Close()
+
+	if n.TLS == EmailTLSStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return fmt.Errorf("SMTP server does not support STARTTLS")
+		}
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return fmt.Errorf("failed to start TLS: %w", err)
+		}
+	}
+	if n.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", n.Username, n.Password, n.Host)); err != nil {
+			return fmt.Errorf("SMTP authentication failed: %w", err)
+		}
+	}
+
+	if err := client.Mail(from.Address); err != nil {
+		return fmt.Errorf("SMTP server rejected sender: %w", err)
+	}
+	for _, rcpt := range to {
+		if err := client.Rcpt(rcpt.Address); err != nil {
+			return fmt.Errorf("SMTP server rejected recipient %s: %w", rcpt.Address, err)
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	if _, err := w.Write(body); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return client.Quit()
+}
+
+// FormatEmail formats an event as an email
+func FormatEmail(event Event) (EmailMessage, error) {
+	switch event.Type {
+	case EventDown:
+		return formatEmail("🔴 Service DOWN", ColorRed, event.ServiceName, event.Time,
+			emailField{"Service", event.ServiceName},
+			emailField{"URL", event.URL},
+			emailField{"Error", event.Error},
+		)
+	case EventStillDown:
+		return formatEmail("🔴 Service STILL DOWN", ColorRed, event.ServiceName, event.Time,
+			emailField{"Service", event.ServiceName},
+			emailField{"URL", event.URL},
+			emailField{"Down For", formatDowntime(event.Downtime)},
+			emailField{"Error", event.Error},
+		)
+	case EventRecovery:
+		return formatEmail("🟢 Service RECOVERED", ColorGreen, event.ServiceName, event.Time,
+			emailField{"Service", event.ServiceName},
+			emailField{"URL", event.URL},
+			emailField{"Downtime", formatDowntime(event.Downtime)},
+		)
+	case EventDegraded:
+		return formatEmail("🟠 Service DEGRADED", ColorOrange, event.ServiceName, event.Time,
+			emailField{"Service", event.ServiceName},
+			emailField{"URL", event.URL},
+			emailField{"Response Time", formatSlowResponse(event.ResponseTime, event.Threshold)},
+		)
+	case EventCertExpiring:
+		return formatEmail("🟡 Certificate EXPIRING", ColorYellow, event.ServiceName, event.Time,
+			emailField{"Service", event.ServiceName},
+			emailField{"URL", event.URL},
+			emailField{"Expires", formatExpiry(event.CertExpiry, event.Time)},
+			emailField{"Issuer", event.CertIssuer},
+		)
+	}
+	return EmailMessage{}, fmt.Errorf("unsupported event type %q", event.Type)
+}
+
+// formatEmail renders the plain-text and HTML bodies of an email. The title
+// is used without its emoji in the subject.
+func formatEmail(title string, color int, name string, t time.Time, fields ...emailField) (EmailMessage, error) {
+	fields = append(fields, emailField{"Time", t.Format("2006-01-02 15:04:05")})
+
+	var text strings.Builder
+	text.WriteString(title + "\n\n")
+	for _, field := range fields {
+		fmt.Fprintf(&text, "%s: %s\n", field.Name, field.Value)
+	}
+
+	var html bytes.Buffer
+	err := emailHTML.Execute(&html, struct {
+		Title  string
+		Color  string
+		Fields []emailField
+	}{title, fmt.Sprintf("#%06X", color), fields})
+	if err != nil {
+		return EmailMessage{}, fmt.Errorf("failed to render email: %w", err)
+	}
+
+	_, plainTitle, _ := strings.Cut(title, " ")
+	return EmailMessage{
+		Subject: fmt.Sprintf("[SENTINEL] %s: %s", plainTitle, name),
+		Text:    text.String(),
+		HTML:    html.String(),
+	}, nil
+}
+
+// buildEmail builds a MIME multipart/alternative message with the plain-text
+// and HTML bodies, both quoted-printable encoded
+func buildEmail(from *mail.Address, to []*mail.Address, message EmailMessage, date time.Time) ([]byte, error) {
+	var body bytes.Buffer
+	parts := multipart.NewWriter(&body)
+
+	for _, part := range []struct{ contentType, content string }{
+		{"text/plain; charset=UTF-8", message.Text},
+		{"text/html; charset=UTF-8", message.HTML},
+	} {
+		w, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qp := quotedprintable.NewWriter(w)
+		if _, err := qp.Write([]byte(part.content)); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := parts.Close(); err != nil {
+		return nil, err
+	}
+
+	recipients := make([]string, len(to))
+	for i, address := range to {
+		recipients[i] = address.String()
+	}
+	if date.IsZero() {
+		date = time.Now()
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", from.String())
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(recipients, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("UTF-8", message.Subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	fmt.Fprintf(&msg, "Message-ID: %s\r\n", messageID(from))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&msg, "Content-Type: multipart/alternative; boundary=%q\r\n", parts.Boundary())
+	msg.WriteString("\r\n")
+	msg.Write(body.Bytes())
+	return msg.Bytes(), nil
+}
+
+// messageID returns a unique Message-ID in the domain of the sender
+func messageID(from *mail.Address) string {
+	domain := "sentinel"
+	if _, d, ok := strings.Cut(from.Address, "@"); ok {
+		domain = d
+	}
+	random := make([]byte, 12)
+	rand.Read(random)
+	return fmt.Sprintf("<%s@%s>", hex.EncodeToString(random), domain)
+}
@@ -0,0 +1,325 @@
+package notifier
+
+import (
+	"bufio"
+	"crypto/tls"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/http/httptest"
+	"net/mail"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0xReLogic/SENTINEL/config"
+)
+
+// fakeSMTPServer is an in-process SMTP server that records one message per
+// session. It supports STARTTLS, implicit TLS and AUTH PLAIN.
+type fakeSMTPServer struct {
+	listener net.Listener
+	tls      *tls.Config
+
+	sessions chan smtpSession
+}
+
+// smtpSession is what a client sent in one session
+type smtpSession struct {
+	tls  bool
+	auth string
+	from string
+	to   []string
+	data string
+}
+
+func newFakeSMTPServer(t *testing.T, implicitTLS bool) *fakeSMTPServer {
+	t.Helper()
+
+	// Borrow the self-signed certificate of an httptest TLS server
+	https := httptest.NewTLSServer(nil)
+	t.Cleanup(https.Close)
+	tlsConfig := &tls.Config{Certificates: https.TLS.Certificates}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	if implicitTLS {
+		listener = tls.NewListener(listener, tlsConfig)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	s := &fakeSMTPServer{listener: listener, tls: tlsConfig, sessions: make(chan smtpSession, 1)}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn, implicitTLS)
+		}
+	}()
+	return s
+}
+
+func (s *fakeSMTPServer) serve(conn net.Conn, secure bool) {
+	defer conn.Close()
+	session := smtpSession{tls: secure}
+	r := bufio.NewReader(conn)
+	reply := func(line string) { io.WriteString(conn, line+"\r\n") }
+
+	reply("220 localhost ESMTP fake")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		verb := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+
+		switch verb {
+		case "EHLO", "HELO":
+			if session.tls {
+				reply("250-localhost\r\n250 AUTH PLAIN")
+			} else {
+				reply("250-localhost\r\n250-STARTTLS\r\n250 AUTH PLAIN")
+			}
+		case "STARTTLS":
+			reply("220 Ready to start TLS")
+			tlsConn := tls.Server(conn, s.tls)
+			if err := tlsConn.Handshake(); err != nil {
+				return
+			}
+			conn, r, session.tls = tlsConn, bufio.NewReader(tlsConn), true
+		case "AUTH":
+			// AUTH PLAIN <base64 of "\x00user\x00password">
+			fields := strings.Fields(line)
+			decoded, _ := base64.StdEncoding.DecodeString(fields[len(fields)-1])
+			session.auth = strings.ReplaceAll(string(decoded), "\x00", ":")
+			reply("235 Authentication successful")
+		case "MAIL":
+			session.from = strings.Trim(strings.TrimPrefix(line, "MAIL FROM:"), "<> ")
+			reply("250 OK")
+		case "RCPT":
+			session.to = append(session.to, strings.Trim(strings.TrimPrefix(line, "RCPT TO:"), "<> "))
+			reply("250 OK")
+		case "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				dataLine, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if dataLine == ".\r\n" {
+					break
+				}
+				data.WriteString(strings.TrimPrefix(dataLine, "."))
+			}
+			session.data = data.String()
+			reply("250 OK")
+		case "QUIT":
+			reply("221 Bye")
+			s.sessions <- session
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+// notifier returns a notifier for the server that trusts its certificate
+func (s *fakeSMTPServer) notifier(mode string) *EmailNotifier {
+	host, port, _ := net.SplitHostPort(s.listener.Addr().String())
+	p, _ := strconv.Atoi(port)
+	n := NewEmailNotifier(config.EmailConfig{
+		Host:     host,
+		Port:     p,
+		TLS:      mode,
+		Username: "sentinel",
+		Password: "s3cret",
+		From:     "SENTINEL <sentinel@example.com>",
+		To:       []string{"ops@example.com", "Manager <boss@example.com>"},
+	})
+	n.tlsConfig = &tls.Config{InsecureSkipVerify: true}
+	return n
+}
+
+func (s *fakeSMTPServer) session(t *testing.T) smtpSession {
+	t.Helper()
+	select {
+	case session := <-s.sessions:
+		return session
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for the email")
+	}
+	return smtpSession{}
+}
+
+// readEmail parses a message into its subject and decoded parts by content type
+func readEmail(t *testing.T, data string) (string, map[string]string) {
+	t.Helper()
+	msg, err := mail.ReadMessage(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("Failed to parse email: %v", err)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil {
+		t.Fatalf("Failed to decode subject: %v", err)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("Expected multipart/alternative, got %q: %v", msg.Header.Get("Content-Type"), err)
+	}
+	parts := make(map[string]string)
+	reader := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		part, err := reader.NextRawPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Failed to read part: %v", err)
+		}
+		content, _ := io.ReadAll(quotedprintable.NewReader(part))
+		contentType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		parts[contentType] = string(content)
+	}
+	return subject, parts
+}
+
+func TestFormatEmail(t *testing.T) {
+	checkTime := time.Date(2025, 10, 11, 22, 30, 0, 0, time.UTC)
+
+	down, err := FormatEmail(Event{Type: EventDown, ServiceName: "API <eu>", URL: testServiceURL, Error: "connection refused", Time: checkTime})
+	if err != nil {
+		t.Fatalf("FormatEmail failed: %v", err)
+	}
+	if down.Subject != "[SENTINEL] Service DOWN: API <eu>" {
+		t.Errorf("Unexpected subject: %q", down.Subject)
+	}
+	wantText := "🔴 Service DOWN\n\nService: API <eu>\nURL: " + testServiceURL + "\nError: connection refused\nTime: 2025-10-11 22:30:00\n"
+	if down.Text != wantText {
+		t.Errorf("Expected text %q, got %q", wantText, down.Text)
+	}
+	if !strings.Contains(down.HTML, "API &lt;eu&gt;") || !strings.Contains(down.HTML, "#E74C3C") {
+		t.Errorf("Expected escaped name and red title in HTML, got %s", down.HTML)
+	}
+
+	recovery, err := FormatEmail(Event{Type: EventRecovery, ServiceName: testServiceName, URL: testServiceURL, Downtime: 45 * time.Minute, Time: checkTime})
+	if err != nil {
+		t.Fatalf("FormatEmail failed: %v", err)
+	}
+	if recovery.Subject != "[SENTINEL] Service RECOVERED: "+testServiceName || !strings.Contains(recovery.Text, "Downtime: 45m") {
+		t.Errorf("Unexpected recovery email: %+v", recovery)
+	}
+	if !strings.Contains(recovery.HTML, "#2ECC71") {
+		t.Errorf("Expected a green title in HTML, got %s", recovery.HTML)
+	}
+
+	if _, err := FormatEmail(Event{Type: "unknown"}); err == nil {
+		t.Error("Expected an error for an unknown event type")
+	}
+}
+
+func TestEmailNotifierStartTLS(t *testing.T) {
+	server := newFakeSMTPServer(t, false)
+
+	event := Event{Type: EventDown, ServiceName: testServiceName, URL: testServiceURL, Error: "connection refused", Time: time.Now()}
+	if err := server.notifier(EmailTLSStartTLS).Notify(event); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	session := server.session(t)
+	if !session.tls {
+		t.Error("Expected the session to be upgraded with STARTTLS")
+	}
+	if session.auth != ":sentinel:s3cret" {
+		t.Errorf("Expected PLAIN auth for sentinel, got %q", session.auth)
+	}
+	if session.from != "sentinel@example.com" || strings.Join(session.to, ",") != "ops@example.com,boss@example.com" {
+		t.Errorf("Unexpected envelope: from %q to %v", session.from, session.to)
+	}
+
+	subject, parts := readEmail(t, session.data)
+	if subject != "[SENTINEL] Service DOWN: "+testServiceName {
+		t.Errorf("Unexpected subject: %q", subject)
+	}
+	if !strings.Contains(parts["text/plain"], "Error: connection refused") {
+		t.Errorf("Expected the error in the plain-text body, got %q", parts["text/plain"])
+	}
+	if !strings.Contains(parts["text/html"], "<h2") || !strings.Contains(parts["text/html"], "connection refused") {
+		t.Errorf("Expected the error in the HTML body, got %q", parts["text/html"])
+	}
+}
+
+func TestEmailNotifierImplicitTLS(t *testing.T) {
+	server := newFakeSMTPServer(t, true)
+
+	event := Event{Type: EventRecovery, ServiceName: testServiceName, URL: testServiceURL, Downtime: 5 * time.Minute, Time: time.Now()}
+	if err := server.notifier(EmailTLSImplicit).Notify(event); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	session := server.session(t)
+	subject, parts := readEmail(t, session.data)
+	if !session.tls || subject != "[SENTINEL] Service RECOVERED: "+testServiceName {
+		t.Errorf("Expected a RECOVERED email over TLS, got subject %q (tls %v)", subject, session.tls)
+	}
+	if !strings.Contains(parts["text/plain"], "Downtime: 5m") {
+		t.Errorf("Expected the downtime in the plain-text body, got %q", parts["text/plain"])
+	}
+}
+
+func TestEmailNotifierNoTLS(t *testing.T) {
+	server := newFakeSMTPServer(t, false)
+
+	n := server.notifier(EmailTLSNone)
+	n.Username = ""
+	if err := n.Notify(testEvents()[0]); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if session := server.session(t); session.tls || session.auth != "" {
+		t.Errorf("Expected a plain session without auth, got tls %v auth %q", session.tls, session.auth)
+	}
+}
+
+func TestEmailValidate(t *testing.T) {
+	valid := config.EmailConfig{Host: "smtp.example.com", From: "sentinel@example.com", To: []string{"ops@example.com"}}
+	if errs := NewEmailNotifier(valid).Validate(); len(errs) != 0 {
+		t.Errorf("Expected no errors, got %v", errs)
+	}
+
+	ports := map[string]int{"": 587, "STARTTLS": 587, "tls": 465, "none": 25}
+	for mode, port := range ports {
+		cfg := valid
+		cfg.TLS = mode
+		if n := NewEmailNotifier(cfg); n.Port != port {
+			t.Errorf("Expected port %d for tls %q, got %d", port, mode, n.Port)
+		}
+	}
+
+	invalid := config.EmailConfig{Port: 70000, TLS: "ssl", From: "not an address", To: []string{"ops@example.com", "@"}}
+	want := []string{
+		"host is required",
+		"invalid port 70000",
+		"unsupported tls 'ssl', expected starttls, tls or none",
+		"invalid from address 'not an address'",
+		"invalid to address '@'",
+	}
+	errs := NewEmailNotifier(invalid).Validate()
+	if len(errs) != len(want) {
+		t.Fatalf("Expected %d errors, got %v", len(want), errs)
+	}
+	for i, err := range errs {
+		if err.Error() != want[i] {
+			t.Errorf("Expected %q, got %q", want[i], err.Error())
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package notifier
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/0xReLogic/SENTINEL/config"
+)
+
+// EventType identifies what a notification is about. Apart from still_down,
+// the values match the notify_on settings of the channels.
+type EventType string
+
+// Notification events
+const (
+	EventDown         EventType = "down"
+	EventStillDown    EventType = "still_down"
+	EventRecovery     EventType = "recovery"
+	EventDegraded     EventType = "degraded"
+	EventCertExpiring EventType = "cert_expiry"
+)
+
+// Event describes something that happened to a service, independent of the
+// channel it is sent to. Only the fields of its type are set.
+type Event struct {
+	Type        EventType
+	ServiceID   string
+	ServiceName string
+	URL         string
+	Time        time.Time
+
+	Error        string        // why the service is down: down, still_down
+	StatusCode   int           // HTTP status code of the check, 0 if there was none
+	Downtime     time.Duration // how long the service is or was down: still_down, recovery
+	ResponseTime time.Duration // degraded
+	Threshold    time.Duration // degraded threshold: degraded
+	CertExpiry   time.Time     // cert_expiry
+	CertIssuer   string        // cert_expiry
+}
+
+// Notifier sends events to a notification channel
+type Notifier interface {
+	Notify(event Event) error
+}
+
+// Validator is implemented by notifiers that validate their settings. Each
+// returned error describes one problem.
+type Validator interface {
+	Validate() []error
+}
+
+// NotifierFunc adapts an ordinary function to the Notifier interface
+type NotifierFunc func(event Event) error
+
+// Notify calls f(event)
+func (f NotifierFunc) Notify(event Event) error {
+	return f(event)
+}
+
+// Channel is an enabled notification channel with the settings that decide
+// which events reach it
+type Channel struct {
+	Name      string
+	Notifier  Notifier
+	NotifyOn  []string
+	Reminders config.Reminders
+}
+
+// Factory builds a channel from the notification settings. It returns false
+// when the channel is not enabled.
+type Factory func(cfg config.NotificationConfig) (Channel, bool)
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Factory)
+)
+
+// Register makes a notification channel available under the given name.
+// It panics if the factory is nil or the name is already registered.
+func Register(name string, f Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if f == nil {
+		panic("notifier: Register factory is nil")
+	}
+	if _, dup := registry[name]; dup {
+		panic("notifier: Register called twice for channel " + name)
+	}
+	registry[name] = f
+}
+
+// Names returns the sorted list of registered channel names
+func Names() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Build returns the named channel if it is registered and enabled
+func Build(name string, cfg config.NotificationConfig) (Channel, bool) {
+	registryMu.RLock()
+	f, ok := registry[name]
+	registryMu.RUnlock()
+	if !ok {
+		return Channel{}, false
+	}
+	channel, enabled := f(cfg)
+	channel.Name = name
+	return channel, enabled
+}
+
+// Enabled returns every enabled channel, sorted by name
+func Enabled(cfg config.NotificationConfig) []Channel {
+	var channels []Channel
+	for _, name := range Names() {
+		if channel, ok := Build(name, cfg); ok {
+			channels = append(channels, channel)
+		}
+	}
+	return channels
+}
@@ -0,0 +1,132 @@
+package notifier
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0xReLogic/SENTINEL/config"
+)
+
+func testEvents() []Event {
+	now := time.Date(2025, 10, 12, 9, 30, 0, 0, time.UTC)
+	return []Event{
+		{Type: EventDown, ServiceName: testServiceName, URL: testServiceURL, Time: now, Error: "connection refused"},
+		{Type: EventStillDown, ServiceName: testServiceName, URL: testServiceURL, Time: now, Error: "connection refused", Downtime: 30 * time.Minute},
+		{Type: EventRecovery, ServiceName: testServiceName, URL: testServiceURL, Time: now, Downtime: 45 * time.Minute},
+		{Type: EventDegraded, ServiceName: testServiceName, URL: testServiceURL, Time: now, ResponseTime: 1500 * time.Millisecond, Threshold: time.Second},
+		{Type: EventCertExpiring, ServiceName: testServiceName, URL: testServiceURL, Time: now, CertExpiry: now.Add(72 * time.Hour), CertIssuer: "Test CA"},
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	names := Names()
+	for _, name := range []string{config.ChannelDiscord, config.ChannelTelegram} {
+		found := false
+		for _, registered := range names {
+			found = found || registered == name
+		}
+		if !found {
+			t.Errorf("Expected %q to be registered, got %v", name, names)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected registering a channel twice to panic")
+		}
+	}()
+	Register(config.ChannelTelegram, func(config.NotificationConfig) (Channel, bool) { return Channel{}, false })
+}
+
+func TestEnabled(t *testing.T) {
+	cfg := config.NotificationConfig{
+		Telegram: config.TelegramConfig{Enabled: true, BotToken: testToken, ChatID: testChatID, NotifyOn: []string{"down"}},
+		Discord:  config.DiscordConfig{Enabled: false, WebhookURL: testURL},
+	}
+
+	channels := Enabled(cfg)
+	if len(channels) != 1 || channels[0].Name != config.ChannelTelegram {
+		t.Fatalf("Expected only the telegram channel, got %+v", channels)
+	}
+	if !reflect.DeepEqual(channels[0].NotifyOn, []string{"down"}) {
+		t.Errorf("Expected notify_on [down], got %v", channels[0].NotifyOn)
+	}
+
+	if _, ok := Build("pager", cfg); ok {
+		t.Error("Expected an unregistered channel not to build")
+	}
+}
+
+func TestFormatTelegramMessage(t *testing.T) {
+	titles := []string{"Service DOWN", "Service STILL DOWN", "Service RECOVERED", "Service DEGRADED", "Certificate EXPIRING"}
+	for i, event := range testEvents() {
+		message, err := FormatTelegramMessage(event)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", event.Type, err)
+		}
+		if !strings.Contains(message, titles[i]) {
+			t.Errorf("%s: expected %q in message, got %q", event.Type, titles[i], message)
+		}
+	}
+
+	if _, err := FormatTelegramMessage(Event{Type: "unknown"}); err == nil {
+		t.Error("Expected an error for an unknown event type")
+	}
+}
+
+func TestFormatDiscordEmbed(t *testing.T) {
+	titles := []string{"Service DOWN", "Service STILL DOWN", "Service RECOVERED", "Service DEGRADED", "Certificate EXPIRING"}
+	for i, event := range testEvents() {
+		embed, err := FormatDiscordEmbed(event)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", event.Type, err)
+		}
+		if !strings.Contains(embed.Title, titles[i]) {
+			t.Errorf("%s: expected %q in title, got %q", event.Type, titles[i], embed.Title)
+		}
+	}
+
+	if _, err := FormatDiscordEmbed(Event{Type: "unknown"}); err == nil {
+		t.Error("Expected an error for an unknown event type")
+	}
+}
+
+func TestTelegramNotifier(t *testing.T) {
+	var text string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		text = r.FormValue("text")
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	n := &TelegramNotifier{BotToken: testToken, ChatID: testChatID, apiURL: server.URL}
+	if err := n.Notify(testEvents()[0]); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !strings.Contains(text, "Service DOWN") {
+		t.Errorf("Expected a DOWN message, got %q", text)
+	}
+}
+
+func TestDiscordNotifier(t *testing.T) {
+	var payload DiscordWebhookPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&payload)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	n := NewDiscordNotifier(config.DiscordConfig{WebhookURL: server.URL})
+	if err := n.Notify(testEvents()[2]); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(payload.Embeds) != 1 || !strings.Contains(payload.Embeds[0].Title, "RECOVERED") {
+		t.Errorf("Expected a RECOVERED embed, got %+v", payload.Embeds)
+	}
+}
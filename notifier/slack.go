@@ -0,0 +1,213 @@
+package notifier
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/0xReLogic/SENTINEL/config"
+)
+
+// Slack attachment colors, matching the Discord embed colors
+const (
+	SlackColorRed    = "#E74C3C" // DOWN status
+	SlackColorGreen  = "#2ECC71" // RECOVERY status
+	SlackColorOrange = "#E67E22" // DEGRADED status
+	SlackColorYellow = "#F1C40F" // Certificate expiring
+)
+
+// SlackMessage represents the payload sent to a Slack incoming webhook. Text
+// is the fallback shown in notifications, the attachment carries the Block
+// Kit blocks and the color bar.
+type SlackMessage struct {
+	Text        string            `json:"text"`
+	Attachments []SlackAttachment `json:"attachments"`
+}
+
+// SlackAttachment represents a colored Slack attachment
+type SlackAttachment struct {
+	Color  string       `json:"color"`
+	Blocks []SlackBlock `json:"blocks"`
+}
+
+// SlackBlock represents a Block Kit block: a header, a section with fields
+// or a context line
+type SlackBlock struct {
+	Type     string      `json:"type"`
+	Text     *SlackText  `json:"text,omitempty"`
+	Fields   []SlackText `json:"fields,omitempty"`
+	Elements []SlackText `json:"elements,omitempty"`
+}
+
+// SlackText represents a Block Kit text object
+type SlackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+func init() {
+	Register(config.ChannelSlack, func(cfg config.NotificationConfig) (Channel, bool) {
+		return Channel{
+			Notifier:  NewSlackNotifier(cfg.Slack),
+			NotifyOn:  cfg.Slack.NotifyOn,
+			Reminders: cfg.Slack.Reminders,
+		}, cfg.Slack.Enabled
+	})
+}
+
+// SlackNotifier sends events as Block Kit messages to a Slack incoming webhook
+type SlackNotifier struct {
+	WebhookURL string
+}
+
+// NewSlackNotifier creates a notifier for the webhook of a Slack configuration
+func NewSlackNotifier(cfg config.SlackConfig) *SlackNotifier {
+	return &SlackNotifier{WebhookURL: cfg.WebhookURL}
+}
+
+// Validate checks that the webhook is an http(s) URL
+func (n *SlackNotifier) Validate() []error {
+	if n.WebhookURL == "" {
+		return []error{fmt.Errorf("webhook_url is required")}
+	}
+	if u, err := url.Parse(n.WebhookURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return []error{fmt.Errorf("invalid webhook_url '%s'", n.WebhookURL)}
+	}
+	return nil
+}
+
+// Notify formats the event and posts it to the webhook
+func (n *SlackNotifier) Notify(event Event) error {
+	message, err := FormatSlackMessage(event)
+	if err != nil {
+		return err
+	}
+	return SendSlackNotification(n.WebhookURL, message)
+}
+
+// FormatSlackMessage formats an event as a Slack message
+func FormatSlackMessage(event Event) (SlackMessage, error) {
+	switch event.Type {
+	case EventDown:
+		return FormatSlackDownMessage(event.ServiceName, event.URL, event.Error, event.Time), nil
+	case EventStillDown:
+		return FormatSlackStillDownMessage(event.ServiceName, event.URL, event.Error, event.Downtime, event.Time), nil
+	case EventRecovery:
+		return FormatSlackRecoveryMessage(event.ServiceName, event.URL, event.Downtime, event.Time), nil
+	case EventDegraded:
+		return FormatSlackDegradedMessage(event.ServiceName, event.URL, event.ResponseTime, event.Threshold, event.Time), nil
+	case EventCertExpiring:
+		return FormatSlackCertExpiryMessage(event.ServiceName, event.URL, event.CertExpiry, event.CertIssuer, event.Time), nil
+	}
+	return SlackMessage{}, fmt.Errorf("unsupported event type %q", event.Type)
+}
+
+// SendSlackNotification posts a message to a Slack incoming webhook
+func SendSlackNotification(webhookURL string, message SlackMessage) error {
+	jsonData, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal Slack payload: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", webhookURL, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send Slack request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("Slack API returned status code %d: %s", resp.StatusCode, string(body))
+	}
+
+	return nil
+}
+
+// FormatSlackDownMessage creates a Slack message for service DOWN notification
+func FormatSlackDownMessage(name, url, errorMsg string, checkTime time.Time) SlackMessage {
+	return slackMessage("🔴 Service DOWN", SlackColorRed, name, checkTime,
+		slackField("Service", name),
+		slackField("URL", url),
+		slackField("Error", errorMsg),
+	)
+}
+
+// FormatSlackStillDownMessage creates a Slack message reminding that a service is still DOWN
+func FormatSlackStillDownMessage(name, url, errorMsg string, downtime time.Duration, checkTime time.Time) SlackMessage {
+	return slackMessage("🔴 Service STILL DOWN", SlackColorRed, name, checkTime,
+		slackField("Service", name),
+		slackField("URL", url),
+		slackField("Down For", formatDowntime(downtime)),
+		slackField("Error", errorMsg),
+	)
+}
+
+// FormatSlackRecoveryMessage creates a Slack message for service RECOVERY notification
+func FormatSlackRecoveryMessage(name, url string, downtime time.Duration, recoveryTime time.Time) SlackMessage {
+	return slackMessage("🟢 Service RECOVERED", SlackColorGreen, name, recoveryTime,
+		slackField("Service", name),
+		slackField("URL", url),
+		slackField("Downtime", formatDowntime(downtime)),
+	)
+}
+
+// FormatSlackDegradedMessage creates a Slack message for service DEGRADED notification
+func FormatSlackDegradedMessage(name, url string, responseTime, threshold time.Duration, checkTime time.Time) SlackMessage {
+	return slackMessage("🟠 Service DEGRADED", SlackColorOrange, name, checkTime,
+		slackField("Service", name),
+		slackField("URL", url),
+		slackField("Response Time", formatSlowResponse(responseTime, threshold)),
+	)
+}
+
+// FormatSlackCertExpiryMessage creates a Slack message for a certificate expiry warning
+func FormatSlackCertExpiryMessage(name, url string, notAfter time.Time, issuer string, checkTime time.Time) SlackMessage {
+	return slackMessage("🟡 Certificate EXPIRING", SlackColorYellow, name, checkTime,
+		slackField("Service", name),
+		slackField("URL", url),
+		slackField("Expires", formatExpiry(notAfter, checkTime)),
+		slackField("Issuer", issuer),
+	)
+}
+
+// slackMessage builds a message with a header, the fields and the time in a
+// colored attachment. The title and service name are the fallback text of
+// the notification.
+func slackMessage(title, color, name string, t time.Time, fields ...SlackText) SlackMessage {
+	return SlackMessage{
+		Text: fmt.Sprintf("%s: %s", title, escapeSlack(name)),
+		Attachments: []SlackAttachment{{
+			Color: color,
+			Blocks: []SlackBlock{
+				{Type: "header", Text: &SlackText{Type: "plain_text", Text: title}},
+				{Type: "section", Fields: fields},
+				{Type: "context", Elements: []SlackText{{Type: "mrkdwn", Text: "*Time:* " + t.Format("2006-01-02 15:04:05")}}},
+			},
+		}},
+	}
+}
+
+// slackField creates a labelled mrkdwn field of a section block
+func slackField(label, value string) SlackText {
+	return SlackText{Type: "mrkdwn", Text: fmt.Sprintf("*%s:*\n%s", label, escapeSlack(value))}
+}
+
+// slackReplacer escapes the characters Slack treats as control characters in mrkdwn
+var slackReplacer = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// escapeSlack escapes a value for Slack mrkdwn text
+func escapeSlack(s string) string {
+	return slackReplacer.Replace(s)
+}
@@ -0,0 +1,208 @@
+package notifier
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0xReLogic/SENTINEL/config"
+)
+
+func assertSlackFields(t *testing.T, message SlackMessage, expected ...string) {
+	t.Helper()
+	if len(message.Attachments) != 1 || len(message.Attachments[0].Blocks) != 3 {
+		t.Fatalf("Expected 1 attachment with 3 blocks, got %+v", message.Attachments)
+	}
+	fields := message.Attachments[0].Blocks[1].Fields
+	if len(fields) != len(expected) {
+		t.Fatalf("Expected %d fields, got %d", len(expected), len(fields))
+	}
+	for i, field := range fields {
+		if field.Type != "mrkdwn" || field.Text != expected[i] {
+			t.Errorf("Expected field %q, got %q", expected[i], field.Text)
+		}
+	}
+}
+
+func TestFormatSlackDownMessage(t *testing.T) {
+	checkTime := time.Date(2025, 10, 11, 22, 30, 0, 0, time.UTC)
+
+	message := FormatSlackDownMessage(testServiceName, testServiceURL, "connection timeout", checkTime)
+
+	if message.Text != "🔴 Service DOWN: "+testServiceName {
+		t.Errorf("Expected fallback text for %s, got '%s'", testServiceName, message.Text)
+	}
+	assertSlackFields(t, message,
+		"*Service:*\n"+testServiceName,
+		"*URL:*\n"+testServiceURL,
+		"*Error:*\nconnection timeout",
+	)
+
+	attachment := message.Attachments[0]
+	if attachment.Color != SlackColorRed {
+		t.Errorf("Expected color %s, got %s", SlackColorRed, attachment.Color)
+	}
+	header := attachment.Blocks[0]
+	if header.Type != "header" || header.Text.Type != "plain_text" || header.Text.Text != "🔴 Service DOWN" {
+		t.Errorf("Expected a plain_text header '🔴 Service DOWN', got %+v", header)
+	}
+	context := attachment.Blocks[2]
+	if context.Type != "context" || len(context.Elements) != 1 || context.Elements[0].Text != "*Time:* 2025-10-11 22:30:00" {
+		t.Errorf("Expected a context block with the time, got %+v", context)
+	}
+}
+
+func TestFormatSlackRecoveryMessage(t *testing.T) {
+	message := FormatSlackRecoveryMessage(testServiceName, testServiceURL, 45*time.Minute, time.Now())
+
+	if message.Attachments[0].Color != SlackColorGreen {
+		t.Errorf("Expected color %s, got %s", SlackColorGreen, message.Attachments[0].Color)
+	}
+	assertSlackFields(t, message,
+		"*Service:*\n"+testServiceName,
+		"*URL:*\n"+testServiceURL,
+		"*Downtime:*\n45m",
+	)
+}
+
+func TestFormatSlackDegradedMessage(t *testing.T) {
+	message := FormatSlackDegradedMessage(testServiceName, testServiceURL, 1500*time.Millisecond, time.Second, time.Now())
+
+	if message.Attachments[0].Color != SlackColorOrange {
+		t.Errorf("Expected color %s, got %s", SlackColorOrange, message.Attachments[0].Color)
+	}
+	assertSlackFields(t, message,
+		"*Service:*\n"+testServiceName,
+		"*URL:*\n"+testServiceURL,
+		"*Response Time:*\n"+formatSlowResponse(1500*time.Millisecond, time.Second),
+	)
+}
+
+func TestFormatSlackMessageEscapes(t *testing.T) {
+	message := FormatSlackDownMessage("A&B <api>", testServiceURL, "expected <200>", time.Now())
+
+	if message.Text != "🔴 Service DOWN: A&amp;B &lt;api&gt;" {
+		t.Errorf("Expected escaped fallback text, got '%s'", message.Text)
+	}
+	assertSlackFields(t, message,
+		"*Service:*\nA&amp;B &lt;api&gt;",
+		"*URL:*\n"+testServiceURL,
+		"*Error:*\nexpected &lt;200&gt;",
+	)
+}
+
+func TestFormatSlackMessage(t *testing.T) {
+	titles := []string{"Service DOWN", "Service STILL DOWN", "Service RECOVERED", "Service DEGRADED", "Certificate EXPIRING"}
+	for i, event := range testEvents() {
+		message, err := FormatSlackMessage(event)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", event.Type, err)
+		}
+		if !strings.Contains(message.Attachments[0].Blocks[0].Text.Text, titles[i]) {
+			t.Errorf("%s: expected %q in header, got %+v", event.Type, titles[i], message.Attachments[0].Blocks[0])
+		}
+	}
+
+	if _, err := FormatSlackMessage(Event{Type: "unknown"}); err == nil {
+		t.Error("Expected an error for an unknown event type")
+	}
+}
+
+func TestSendSlackNotificationSuccess(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Errorf("Expected POST request, got %s", r.Method)
+		}
+
+		if r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Expected Content-Type 'application/json', got '%s'", r.Header.Get("Content-Type"))
+		}
+
+		var payload SlackMessage
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Fatalf("Failed to decode payload: %v", err)
+		}
+
+		if len(payload.Attachments) != 1 || payload.Attachments[0].Color != SlackColorRed {
+			t.Errorf("Expected 1 red attachment, got %+v", payload.Attachments)
+		}
+
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	message := FormatSlackDownMessage("Test", testURL, "error", time.Now())
+	if err := SendSlackNotification(server.URL, message); err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+}
+
+func TestSendSlackNotificationAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("no_service"))
+	}))
+	defer server.Close()
+
+	message := FormatSlackDownMessage("Test", testURL, "error", time.Now())
+	err := SendSlackNotification(server.URL, message)
+	if err == nil {
+		t.Fatal("Expected an error for failed API call, but got nil")
+	}
+
+	if !strings.Contains(err.Error(), "Slack API returned status code 404: no_service") {
+		t.Errorf("Expected error to contain 'Slack API returned status code 404: no_service', got: %v", err)
+	}
+}
+
+func TestSlackNotifier(t *testing.T) {
+	var payload SlackMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&payload)
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	channel, ok := Build(config.ChannelSlack, config.NotificationConfig{
+		Slack: config.SlackConfig{Enabled: true, WebhookURL: server.URL, NotifyOn: []string{"down", "recovery"}},
+	})
+	if !ok {
+		t.Fatal("Expected the slack channel to be enabled")
+	}
+	if err := channel.Notifier.Notify(testEvents()[2]); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if payload.Attachments[0].Color != SlackColorGreen {
+		t.Errorf("Expected a green RECOVERED attachment, got %+v", payload)
+	}
+}
+
+func TestSlackValidate(t *testing.T) {
+	tests := []struct {
+		url     string
+		wantErr string
+	}{
+		{url: testURL},
+		{url: "", wantErr: "webhook_url is required"},
+		{url: "hooks.slack.com/services/T000/B000/XXXX", wantErr: "invalid webhook_url"},
+		{url: "https://", wantErr: "invalid webhook_url"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			errs := NewSlackNotifier(config.SlackConfig{WebhookURL: tt.url}).Validate()
+			if tt.wantErr == "" {
+				if len(errs) != 0 {
+					t.Errorf("Expected no errors, got %v", errs)
+				}
+				return
+			}
+			if len(errs) != 1 || !strings.Contains(errs[0].Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, errs)
+			}
+		})
+	}
+}
@@ -7,8 +7,9 @@ import (
 	"net/url"
 	"strings"
 	"time"
-)
 
+	"github.com/0xReLogic/SENTINEL/config"
+)
 
 var markdownReplacer = strings.NewReplacer(
 	"_", "\\_", "*", "\\*", "[", "\\[", "]", "\\]", "(",
@@ -22,6 +23,57 @@ func escapeMarkdownV2(s string) string {
 	return markdownReplacer.Replace(s)
 }
 
+func init() {
+	Register(config.ChannelTelegram, func(cfg config.NotificationConfig) (Channel, bool) {
+		return Channel{
+			Notifier:  NewTelegramNotifier(cfg.Telegram),
+			NotifyOn:  cfg.Telegram.NotifyOn,
+			Reminders: cfg.Telegram.Reminders,
+		}, cfg.Telegram.Enabled
+	})
+}
+
+// TelegramNotifier sends events as MarkdownV2 messages to a Telegram chat
+type TelegramNotifier struct {
+	BotToken string
+	ChatID   string
+
+	apiURL string // overrides the Telegram API endpoint in tests
+}
+
+// NewTelegramNotifier creates a notifier for the chat of a Telegram configuration
+func NewTelegramNotifier(cfg config.TelegramConfig) *TelegramNotifier {
+	return &TelegramNotifier{BotToken: cfg.BotToken, ChatID: cfg.ChatID}
+}
+
+// Notify formats the event and sends it to the chat
+func (n *TelegramNotifier) Notify(event Event) error {
+	message, err := FormatTelegramMessage(event)
+	if err != nil {
+		return err
+	}
+	if n.apiURL != "" {
+		return sendTelegramRequest(n.BotToken, n.ChatID, message, n.apiURL)
+	}
+	return SendTelegramNotification(n.BotToken, n.ChatID, message)
+}
+
+// FormatTelegramMessage formats an event as a Telegram message
+func FormatTelegramMessage(event Event) (string, error) {
+	switch event.Type {
+	case EventDown:
+		return FormatDownMessage(event.ServiceName, event.URL, event.Error, event.Time), nil
+	case EventStillDown:
+		return FormatStillDownMessage(event.ServiceName, event.URL, event.Error, event.Downtime, event.Time), nil
+	case EventRecovery:
+		return FormatRecoveryMessage(event.ServiceName, event.URL, event.Downtime, event.Time), nil
+	case EventDegraded:
+		return FormatDegradedMessage(event.ServiceName, event.URL, event.ResponseTime, event.Threshold, event.Time), nil
+	case EventCertExpiring:
+		return FormatCertExpiryMessage(event.ServiceName, event.URL, event.CertExpiry, event.CertIssuer, event.Time), nil
+	}
+	return "", fmt.Errorf("unsupported event type %q", event.Type)
+}
 
 // SendTelegramNotification sends a message to Telegram using the production API URL.
 func SendTelegramNotification(token, chatID, message string) error {
@@ -56,7 +108,6 @@ func sendTelegramRequest(token, chatID, message, apiUrl string) error {
 	return nil
 }
 
-
 // (FormatDownMessage and FormatRecoveryMessage functions remain the same)
 func FormatDownMessage(name, url, errorMsg string, checkTime time.Time) string {
 	return fmt.Sprintf("🔴 *Service DOWN*\n*Name:* %s\n*URL:* %s\n*Error:* %s\n*Time:* %s",
@@ -67,6 +118,16 @@ func FormatDownMessage(name, url, errorMsg string, checkTime time.Time) string {
 	)
 }
 
+func FormatStillDownMessage(name, url, errorMsg string, downtime time.Duration, checkTime time.Time) string {
+	return fmt.Sprintf("🔴 *Service STILL DOWN*\n*Name:* %s\n*URL:* %s\n*Error:* %s\n*Down for:* %s\n*Time:* %s",
+		escapeMarkdownV2(name),
+		escapeMarkdownV2(url),
+		escapeMarkdownV2(errorMsg),
+		escapeMarkdownV2(formatDowntime(downtime)),
+		escapeMarkdownV2(checkTime.Format("2006-01-02 15:04:05")),
+	)
+}
+
 func FormatRecoveryMessage(name, url string, downtime time.Duration, recoveryTime time.Time) string {
 	return fmt.Sprintf("🟢 *Service RECOVERED*\n*Name:* %s\n*URL:* %s\n*Downtime:* %s\n*Time:* %s",
 		escapeMarkdownV2(name),
@@ -74,4 +135,45 @@ func FormatRecoveryMessage(name, url string, downtime time.Duration, recoveryTim
 		escapeMarkdownV2(downtime.String()),
 		escapeMarkdownV2(recoveryTime.Format("2006-01-02 15:04:05")),
 	)
-}
\ No newline at end of file
+}
+
+func FormatDegradedMessage(name, url string, responseTime, threshold time.Duration, checkTime time.Time) string {
+	return fmt.Sprintf("🟠 *Service DEGRADED*\n*Name:* %s\n*URL:* %s\n*Response Time:* %s\n*Time:* %s",
+		escapeMarkdownV2(name),
+		escapeMarkdownV2(url),
+		escapeMarkdownV2(formatSlowResponse(responseTime, threshold)),
+		escapeMarkdownV2(checkTime.Format("2006-01-02 15:04:05")),
+	)
+}
+
+func FormatCertExpiryMessage(name, url string, notAfter time.Time, issuer string, checkTime time.Time) string {
+	return fmt.Sprintf("🟡 *Certificate EXPIRING*\n*Name:* %s\n*URL:* %s\n*Expires:* %s\n*Issuer:* %s\n*Time:* %s",
+		escapeMarkdownV2(name),
+		escapeMarkdownV2(url),
+		escapeMarkdownV2(formatExpiry(notAfter, checkTime)),
+		escapeMarkdownV2(issuer),
+		escapeMarkdownV2(checkTime.Format("2006-01-02 15:04:05")),
+	)
+}
+
+// formatDowntime shortens a long downtime to whole minutes, e.g. "3h12m"
+func formatDowntime(d time.Duration) string {
+	if d < time.Minute {
+		return d.Round(time.Second).String()
+	}
+	return strings.TrimSuffix(d.Truncate(time.Minute).String(), "0s")
+}
+
+// formatSlowResponse describes a response time against the degraded threshold
+func formatSlowResponse(responseTime, threshold time.Duration) string {
+	return fmt.Sprintf("%d ms (threshold %d ms)", responseTime.Milliseconds(), threshold.Milliseconds())
+}
+
+// formatExpiry describes a certificate expiry date relative to the check time
+func formatExpiry(notAfter, checkTime time.Time) string {
+	remaining := notAfter.Sub(checkTime)
+	if remaining <= 0 {
+		return fmt.Sprintf("%s (expired)", notAfter.Format("2006-01-02 15:04:05"))
+	}
+	return fmt.Sprintf("%s (in %d days)", notAfter.Format("2006-01-02 15:04:05"), int(remaining.Hours()/24))
+}
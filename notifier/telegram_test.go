@@ -49,6 +49,66 @@ func TestFormatRecovery(t *testing.T) {
 	}
 }
 
+func TestFormatStillDown(t *testing.T) {
+	checkTime := time.Date(2025, 10, 11, 22, 30, 0, 0, time.UTC)
+	expected := `🔴 *Service STILL DOWN*
+*Name:* Test Down Message
+*URL:* https://api\.test\-service\.com
+*Error:* connection refused
+*Down for:* 3h12m
+*Time:* 2025\-10\-11 22:30:00`
+
+	downtime := 3*time.Hour + 12*time.Minute + 41*time.Second
+	actual := FormatStillDownMessage("Test Down Message", "https://api.test-service.com", "connection refused", downtime, checkTime)
+	if actual != expected {
+		t.Errorf("FormatStillDownMessage() failed:\nExpected:\n%s\nGot:\n%s", expected, actual)
+	}
+}
+
+func TestFormatDowntime(t *testing.T) {
+	tests := map[time.Duration]string{
+		42*time.Second + 300*time.Millisecond: "42s",
+		5*time.Minute + 10*time.Second:        "5m",
+		3*time.Hour + 12*time.Minute:          "3h12m",
+		50 * time.Hour:                        "50h0m",
+	}
+	for d, want := range tests {
+		if got := formatDowntime(d); got != want {
+			t.Errorf("formatDowntime(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
+
+func TestFormatDegraded(t *testing.T) {
+	checkTime := time.Date(2025, 10, 11, 22, 30, 0, 0, time.UTC)
+	expected := `🟠 *Service DEGRADED*
+*Name:* Test Slow Message
+*URL:* https://api\.test\-service\.com
+*Response Time:* 2500 ms \(threshold 1000 ms\)
+*Time:* 2025\-10\-11 22:30:00`
+
+	actual := FormatDegradedMessage("Test Slow Message", "https://api.test-service.com", 2500*time.Millisecond, time.Second, checkTime)
+	if actual != expected {
+		t.Errorf("FormatDegradedMessage() failed:\nExpected:\n%s\nGot:\n%s", expected, actual)
+	}
+}
+
+func TestFormatCertExpiry(t *testing.T) {
+	notAfter := time.Date(2025, 10, 25, 22, 30, 0, 0, time.UTC)
+	checkTime := time.Date(2025, 10, 11, 22, 30, 0, 0, time.UTC)
+	expected := `🟡 *Certificate EXPIRING*
+*Name:* Test Cert Message
+*URL:* https://api\.test\-service\.com
+*Expires:* 2025\-10\-25 22:30:00 \(in 14 days\)
+*Issuer:* CN\=Test CA
+*Time:* 2025\-10\-11 22:30:00`
+
+	actual := FormatCertExpiryMessage("Test Cert Message", "https://api.test-service.com", notAfter, "CN=Test CA", checkTime)
+	if actual != expected {
+		t.Errorf("FormatCertExpiryMessage() failed:\nExpected:\n%s\nGot:\n%s", expected, actual)
+	}
+}
+
 func TestEscapeMarkdownV2(t *testing.T) {
     tests := []struct {
         name  string
@@ -0,0 +1,174 @@
+package notifier
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/0xReLogic/SENTINEL/config"
+)
+
+// Webhook defaults
+const (
+	DefaultWebhookMethod          = "POST"
+	DefaultWebhookSignatureHeader = "X-Sentinel-Signature"
+)
+
+// DefaultWebhookTemplate renders an event as a flat JSON object
+const DefaultWebhookTemplate = `{"event":{{json .Type}},"service_id":{{json .ServiceID}},"service":{{json .ServiceName}},` +
+	`"url":{{json .URL}},"error":{{json .Error}},"status_code":{{.StatusCode}},` +
+	`"downtime_seconds":{{seconds .Downtime}},"time":{{json .Time}}}`
+
+// webhookFuncs are the functions available to webhook templates
+var webhookFuncs = template.FuncMap{
+	// json encodes a value as JSON, e.g. a quoted and escaped string
+	"json": func(v any) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+	// seconds returns a duration in seconds
+	"seconds": func(d time.Duration) float64 {
+		return d.Seconds()
+	},
+	// rfc3339 formats a time in UTC as RFC 3339
+	"rfc3339": func(t time.Time) string {
+		return t.UTC().Format(time.RFC3339)
+	},
+}
+
+func init() {
+	Register(config.ChannelWebhook, func(cfg config.NotificationConfig) (Channel, bool) {
+		return Channel{
+			Notifier:  NewWebhookNotifier(cfg.Webhook),
+			NotifyOn:  cfg.Webhook.NotifyOn,
+			Reminders: cfg.Webhook.Reminders,
+		}, cfg.Webhook.Enabled
+	})
+}
+
+// WebhookNotifier sends events to an HTTP endpoint with a templated body
+type WebhookNotifier struct {
+	URL             string
+	Method          string
+	Headers         map[string]string
+	Secret          string
+	SignatureHeader string
+
+	template *template.Template
+	err      error // template parse error, reported on validation and send
+}
+
+// NewWebhookNotifier creates a notifier from a webhook configuration, filling
+// in the default method, signature header and template
+func NewWebhookNotifier(cfg config.WebhookConfig) *WebhookNotifier {
+	n := &WebhookNotifier{
+		URL:             cfg.URL,
+		Method:          strings.ToUpper(cfg.Method),
+		Headers:         cfg.Headers,
+		Secret:          cfg.Secret,
+		SignatureHeader: cfg.SignatureHeader,
+	}
+	if n.Method == "" {
+		n.Method = DefaultWebhookMethod
+	}
+	if n.SignatureHeader == "" {
+		n.SignatureHeader = DefaultWebhookSignatureHeader
+	}
+
+	text := cfg.Template
+	if text == "" {
+		text = DefaultWebhookTemplate
+	}
+	n.template, n.err = template.New("webhook").Funcs(webhookFuncs).Parse(text)
+	return n
+}
+
+// Validate checks the URL, method and template of the webhook. The template
+// is rendered for a sample event, so unknown fields are caught as well.
+func (n *WebhookNotifier) Validate() []error {
+	var errors []error
+	if n.URL == "" {
+		errors = append(errors, fmt.Errorf("url is required"))
+	} else if u, err := url.Parse(n.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		errors = append(errors, fmt.Errorf("invalid url '%s'", n.URL))
+	}
+	switch n.Method {
+	case "POST", "PUT", "PATCH":
+	default:
+		errors = append(errors, fmt.Errorf("unsupported method '%s', expected POST, PUT or PATCH", n.Method))
+	}
+	if n.err != nil {
+		errors = append(errors, fmt.Errorf("invalid template: %w", n.err))
+	} else if err := n.template.Execute(io.Discard, Event{Type: EventDown, Time: time.Now()}); err != nil {
+		errors = append(errors, fmt.Errorf("invalid template: %w", err))
+	}
+	return errors
+}
+
+// Notify renders the event and sends it to the webhook
+func (n *WebhookNotifier) Notify(event Event) error {
+	body, err := n.Render(event)
+	if err != nil {
+		return err
+	}
+	return n.send(body)
+}
+
+// Render renders the body of an event from the template
+func (n *WebhookNotifier) Render(event Event) ([]byte, error) {
+	if n.err != nil {
+		return nil, fmt.Errorf("invalid webhook template: %w", n.err)
+	}
+	var body bytes.Buffer
+	if err := n.template.Execute(&body, event); err != nil {
+		return nil, fmt.Errorf("failed to render webhook template: %w", err)
+	}
+	return body.Bytes(), nil
+}
+
+func (n *WebhookNotifier) send(body []byte) error {
+	req, err := http.NewRequest(n.Method, n.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range n.Headers {
+		req.Header.Set(name, value)
+	}
+	if n.Secret != "" {
+		req.Header.Set(n.SignatureHeader, SignWebhook(n.Secret, body))
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send webhook request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("webhook returned status code %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	return nil
+}
+
+// SignWebhook returns the signature of a webhook body: "sha256=" followed by
+// the hex encoded HMAC-SHA256 of the body keyed with the secret. Receivers
+// verify a request by computing the same value and comparing it with
+// hmac.Equal.
+func SignWebhook(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
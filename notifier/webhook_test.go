@@ -0,0 +1,163 @@
+package notifier
+
+import (
+	"crypto/hmac"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0xReLogic/SENTINEL/config"
+)
+
+func TestWebhookDefaultTemplate(t *testing.T) {
+	n := NewWebhookNotifier(config.WebhookConfig{URL: testURL})
+	event := Event{
+		Type:        EventStillDown,
+		ServiceID:   "test-service",
+		ServiceName: `Test "Service"`,
+		URL:         testServiceURL,
+		Time:        time.Date(2025, 10, 12, 9, 30, 0, 0, time.UTC),
+		Error:       "HTTP Status Code 503",
+		StatusCode:  503,
+		Downtime:    90 * time.Second,
+	}
+
+	body, err := n.Render(event)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatalf("Expected a JSON body, got %s: %v", body, err)
+	}
+	want := map[string]any{
+		"event":            "still_down",
+		"service_id":       "test-service",
+		"service":          `Test "Service"`,
+		"url":              testServiceURL,
+		"error":            "HTTP Status Code 503",
+		"status_code":      float64(503),
+		"downtime_seconds": float64(90),
+		"time":             "2025-10-12T09:30:00Z",
+	}
+	for key, value := range want {
+		if payload[key] != value {
+			t.Errorf("Expected %s %v, got %v", key, value, payload[key])
+		}
+	}
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	var method, body, signature, token, contentType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		method, body = r.Method, string(b)
+		signature = r.Header.Get("X-Signature")
+		token = r.Header.Get("Authorization")
+		contentType = r.Header.Get("Content-Type")
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	n := NewWebhookNotifier(config.WebhookConfig{
+		URL:             server.URL,
+		Method:          "put",
+		Headers:         map[string]string{"Authorization": "Bearer secret-token", "Content-Type": "text/plain"},
+		Template:        `{{.ServiceName}} is {{.Type}} since {{rfc3339 .Time}}`,
+		Secret:          "shared-secret",
+		SignatureHeader: "X-Signature",
+	})
+	event := Event{Type: EventDown, ServiceName: testServiceName, Time: time.Date(2025, 10, 12, 9, 30, 0, 0, time.UTC)}
+	if err := n.Notify(event); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if method != "PUT" {
+		t.Errorf("Expected PUT request, got %s", method)
+	}
+	if body != "Test Service is down since 2025-10-12T09:30:00Z" {
+		t.Errorf("Unexpected body: %q", body)
+	}
+	if token != "Bearer secret-token" || contentType != "text/plain" {
+		t.Errorf("Expected configured headers, got Authorization %q and Content-Type %q", token, contentType)
+	}
+	if !hmac.Equal([]byte(signature), []byte(SignWebhook("shared-secret", []byte(body)))) {
+		t.Errorf("Signature %q does not match the body", signature)
+	}
+}
+
+func TestSignWebhook(t *testing.T) {
+	// Test vector from RFC 4231, test case 2
+	got := SignWebhook("Jefe", []byte("what do ya want for nothing?"))
+	want := "sha256=5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843"
+	if got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+}
+
+func TestWebhookNotifierUnsigned(t *testing.T) {
+	var signed bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, signed = r.Header[DefaultWebhookSignatureHeader]
+		if r.Method != DefaultWebhookMethod {
+			t.Errorf("Expected %s request, got %s", DefaultWebhookMethod, r.Method)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	if err := NewWebhookNotifier(config.WebhookConfig{URL: server.URL}).Notify(testEvents()[0]); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if signed {
+		t.Error("Expected no signature header without a secret")
+	}
+}
+
+func TestWebhookNotifierAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte("bad signature"))
+	}))
+	defer server.Close()
+
+	err := NewWebhookNotifier(config.WebhookConfig{URL: server.URL}).Notify(testEvents()[0])
+	if err == nil || !strings.Contains(err.Error(), "webhook returned status code 401: bad signature") {
+		t.Errorf("Expected a status code error, got: %v", err)
+	}
+}
+
+func TestWebhookValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config.WebhookConfig
+		wantErr string
+	}{
+		{name: "valid", cfg: config.WebhookConfig{URL: testURL, Method: "patch", Template: `{{json .ServiceName}}`}},
+		{name: "missing url", cfg: config.WebhookConfig{}, wantErr: "url is required"},
+		{name: "invalid url", cfg: config.WebhookConfig{URL: "ftp://example.com"}, wantErr: "invalid url"},
+		{name: "invalid method", cfg: config.WebhookConfig{URL: testURL, Method: "GET"}, wantErr: "unsupported method 'GET'"},
+		{name: "parse error", cfg: config.WebhookConfig{URL: testURL, Template: `{{.ServiceName`}, wantErr: "invalid template"},
+		{name: "unknown field", cfg: config.WebhookConfig{URL: testURL, Template: `{{.Service}}`}, wantErr: "invalid template"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := NewWebhookNotifier(tt.cfg).Validate()
+			if tt.wantErr == "" {
+				if len(errs) != 0 {
+					t.Errorf("Expected no errors, got %v", errs)
+				}
+				return
+			}
+			if len(errs) != 1 || !strings.Contains(errs[0].Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, errs)
+			}
+		})
+	}
+}
@@ -3,9 +3,12 @@ package storage
 import (
 	"database/sql"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/0xReLogic/SENTINEL/checker"
+	"github.com/0xReLogic/SENTINEL/config"
 	_ "modernc.org/sqlite"
 )
 
@@ -23,16 +26,50 @@ CREATE TABLE IF NOT EXISTS checks (
     status_code INTEGER,
     response_time_ms INTEGER,
     error_message TEXT,
-    checked_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+    checked_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    state TEXT NOT NULL DEFAULT '',
+    attempts INTEGER NOT NULL DEFAULT 1,
+    degraded BOOLEAN NOT NULL DEFAULT 0,
+    service_id TEXT NOT NULL DEFAULT '',
+    incident_id INTEGER
 );
 
 CREATE INDEX IF NOT EXISTS idx_service_time ON checks(service_name, checked_at);
 CREATE INDEX IF NOT EXISTS idx_checked_at ON checks(checked_at);
+
+CREATE TABLE IF NOT EXISTS incidents (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    service_id TEXT NOT NULL,
+    service_name TEXT NOT NULL,
+    started_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    ended_at TIMESTAMP,
+    duration_seconds INTEGER,
+    first_error TEXT NOT NULL DEFAULT '',
+    checks INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_incidents_service ON incidents(service_id, started_at);
+
+CREATE TABLE IF NOT EXISTS service_state (
+    service_id TEXT PRIMARY KEY,
+    state TEXT NOT NULL,
+    down_since TIMESTAMP,
+    escalation_step INTEGER NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS notification_state (
+    service_id TEXT NOT NULL,
+    channel TEXT NOT NULL,
+    notified_at TIMESTAMP NOT NULL,
+    reminders INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (service_id, channel)
+);
 `
 
 // NewSQLiteStorage creates a new SQLite storage instance
 func NewSQLiteStorage(dbPath string) (*SQLiteStorage, error) {
-	db, err := sql.Open("sqlite", dbPath)
+	db, err := sql.Open("sqlite", dsn(dbPath))
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
@@ -54,58 +91,340 @@ func NewSQLiteStorage(dbPath string) (*SQLiteStorage, error) {
 		return nil, fmt.Errorf("failed to create schema: %w", err)
 	}
 
+	// Bring databases created by older versions up to date
+	if err := migrate(db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to migrate schema: %w", err)
+	}
+
 	return &SQLiteStorage{db: db}, nil
 }
 
-// SaveCheck saves a service check result to the database
+// busyTimeout is how long a write waits for another connection's write to finish
+const busyTimeout = 5 * time.Second
+
+// dsn adds the connection settings for concurrent writers to a database path.
+// They are applied to every pooled connection, which a PRAGMA run through
+// db.Exec is not: writers wait up to busyTimeout for the lock instead of
+// failing with SQLITE_BUSY, and transactions take the write lock up front
+// with BEGIN IMMEDIATE, so a transaction that reads before it writes cannot
+// fail to upgrade its lock after another writer committed.
+func dsn(dbPath string) string {
+	sep := "?"
+	if strings.Contains(dbPath, "?") {
+		sep = "&"
+	}
+	return fmt.Sprintf("%s%s_pragma=busy_timeout(%d)&_txlock=immediate", dbPath, sep, busyTimeout.Milliseconds())
+}
+
+// columnMigrations lists columns added to tables after their first release,
+// with the definition used to add them to existing databases
+var columnMigrations = []struct {
+	table      string
+	column     string
+	definition string
+}{
+	{"checks", "state", "TEXT NOT NULL DEFAULT ''"},
+	{"checks", "attempts", "INTEGER NOT NULL DEFAULT 1"},
+	{"checks", "degraded", "BOOLEAN NOT NULL DEFAULT 0"},
+	{"checks", "service_id", "TEXT NOT NULL DEFAULT ''"},
+	{"checks", "incident_id", "INTEGER"},
+	{"notification_state", "reminders", "INTEGER NOT NULL DEFAULT 0"},
+	{"service_state", "escalation_step", "INTEGER NOT NULL DEFAULT 0"},
+}
+
+// migrate adds any missing columns and fills in the service ID of rows
+// written before services had one
+func migrate(db *sql.DB) error {
+	existing := make(map[string]map[string]bool)
+	for _, m := range columnMigrations {
+		if existing[m.table] == nil {
+			columns, err := tableColumns(db, m.table)
+			if err != nil {
+				return err
+			}
+			existing[m.table] = columns
+		}
+		if existing[m.table][m.column] {
+			continue
+		}
+		if _, err := db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", m.table, m.column, m.definition)); err != nil {
+			return fmt.Errorf("failed to add column %s.%s: %w", m.table, m.column, err)
+		}
+	}
+
+	if _, err := db.Exec("CREATE INDEX IF NOT EXISTS idx_service_id_time ON checks(service_id, checked_at)"); err != nil {
+		return fmt.Errorf("failed to create service id index: %w", err)
+	}
+	if _, err := db.Exec("CREATE INDEX IF NOT EXISTS idx_incident_id ON checks(incident_id)"); err != nil {
+		return fmt.Errorf("failed to create incident id index: %w", err)
+	}
+
+	return backfillServiceIDs(db)
+}
+
+// tableColumns returns the names of the columns of a table
+func tableColumns(db *sql.DB, table string) (map[string]bool, error) {
+	rows, err := db.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return nil, err
+	}
+
+	existing := make(map[string]bool)
+	for rows.Next() {
+		var (
+			cid       int
+			name      string
+			colType   string
+			notNull   bool
+			dfltValue sql.NullString
+			pk        int
+		)
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &dfltValue, &pk); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		existing[name] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return existing, nil
+}
+
+// backfillServiceIDs derives the service ID of legacy rows from their service name
+func backfillServiceIDs(db *sql.DB) error {
+	rows, err := db.Query("SELECT DISTINCT service_name FROM checks WHERE service_id = ''")
+	if err != nil {
+		return err
+	}
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return err
+		}
+		names = append(names, name)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		if _, err := db.Exec("UPDATE checks SET service_id = ? WHERE service_id = '' AND service_name = ?", config.Slugify(name), name); err != nil {
+			return fmt.Errorf("failed to backfill service id for %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// SaveCheck saves a service check result to the database. A check in the
+// DOWN state opens an incident for the service or joins the open one, and the
+// first check out of the DOWN state closes it.
 func (s *SQLiteStorage) SaveCheck(check checker.ServiceStatus) error {
 	var errorMsg string
 	if check.Error != nil {
 		errorMsg = check.Error.Error()
 	}
 
+	serviceID := check.ID
+	if serviceID == "" {
+		serviceID = config.Slugify(check.Name)
+	}
+
+	attempts := len(check.Attempts)
+	if attempts == 0 {
+		attempts = 1
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to save check: %w", err)
+	}
+	defer tx.Rollback()
+
+	incidentID, err := trackIncident(tx, serviceID, check, errorMsg)
+	if err != nil {
+		return err
+	}
+
 	query := `
-		INSERT INTO checks (service_name, service_url, is_up, status_code, response_time_ms, error_message)
-		VALUES (?, ?, ?, ?, ?, ?)
+		INSERT INTO checks (service_id, service_name, service_url, is_up, status_code, response_time_ms, error_message, state, attempts, degraded, incident_id)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
-	_, err := s.db.Exec(query,
+	_, err = tx.Exec(query,
+		serviceID,
 		check.Name,
 		check.URL,
 		check.IsUp,
 		check.StatusCode,
 		check.ResponseTime.Milliseconds(),
 		errorMsg,
+		check.State,
+		attempts,
+		check.Degraded,
+		incidentID,
 	)
 
 	if err != nil {
 		return fmt.Errorf("failed to save check: %w", err)
 	}
 
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to save check: %w", err)
+	}
 	return nil
 }
 
-// GetHistory retrieves check history for a service
-func (s *SQLiteStorage) GetHistory(serviceName string, limit int) ([]CheckRecord, error) {
-	query := `
-		SELECT id, service_name, service_url, is_up, status_code, response_time_ms, error_message, checked_at
+// trackIncident opens, extends or closes the incident of a service for a
+// check and returns the incident the check belongs to, if any
+func trackIncident(tx *sql.Tx, serviceID string, check checker.ServiceStatus, errorMsg string) (sql.NullInt64, error) {
+	var open sql.NullInt64
+	err := tx.QueryRow(`SELECT id FROM incidents WHERE service_id = ? AND ended_at IS NULL ORDER BY id DESC LIMIT 1`, serviceID).Scan(&open)
+	if err != nil && err != sql.ErrNoRows {
+		return open, fmt.Errorf("failed to query open incident: %w", err)
+	}
+
+	// Thresholds decide when an outage starts and ends, not single failures
+	state := check.State
+	if state == "" {
+		state = check.Result()
+	}
+
+	switch {
+	case state == checker.StateDown && !open.Valid:
+		result, err := tx.Exec(`
+			INSERT INTO incidents (service_id, service_name, first_error, checks)
+			VALUES (?, ?, ?, 1)
+		`, serviceID, check.Name, errorMsg)
+		if err != nil {
+			return open, fmt.Errorf("failed to open incident: %w", err)
+		}
+		id, err := result.LastInsertId()
+		if err != nil {
+			return open, fmt.Errorf("failed to open incident: %w", err)
+		}
+		return sql.NullInt64{Int64: id, Valid: true}, nil
+	case state == checker.StateDown:
+		if _, err := tx.Exec(`UPDATE incidents SET checks = checks + 1 WHERE id = ?`, open.Int64); err != nil {
+			return open, fmt.Errorf("failed to update incident: %w", err)
+		}
+		return open, nil
+	case open.Valid:
+		_, err := tx.Exec(`
+			UPDATE incidents SET
+				ended_at = CURRENT_TIMESTAMP,
+				duration_seconds = CAST(ROUND((julianday(CURRENT_TIMESTAMP) - julianday(started_at)) * 86400) AS INTEGER)
+			WHERE id = ?
+		`, open.Int64)
+		if err != nil {
+			return open, fmt.Errorf("failed to close incident: %w", err)
+		}
+	}
+	return sql.NullInt64{}, nil
+}
+
+// GetHistory retrieves check history for a service by its ID
+func (s *SQLiteStorage) GetHistory(serviceID string, limit int) ([]CheckRecord, error) {
+	return s.QueryHistory(HistoryQuery{ServiceIDs: []string{serviceID}, Limit: limit})
+}
+
+// QueryHistory retrieves the checks matching a query, newest first
+func (s *SQLiteStorage) QueryHistory(query HistoryQuery) ([]CheckRecord, error) {
+	var (
+		conditions []string
+		args       []any
+	)
+	if len(query.ServiceIDs) > 0 {
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(query.ServiceIDs)), ", ")
+		conditions = append(conditions, "service_id IN ("+placeholders+")")
+		for _, id := range query.ServiceIDs {
+			args = append(args, id)
+		}
+	}
+	if !query.Since.IsZero() {
+		conditions = append(conditions, "checked_at >= ?")
+		args = append(args, sqliteTime(query.Since))
+	}
+	if !query.Until.IsZero() {
+		conditions = append(conditions, "checked_at < ?")
+		args = append(args, sqliteTime(query.Until))
+	}
+	switch strings.ToUpper(query.Status) {
+	case "":
+	case checker.StateUp:
+		conditions = append(conditions, "is_up AND NOT degraded")
+	case checker.StateDegraded:
+		conditions = append(conditions, "is_up AND degraded")
+	case checker.StateDown:
+		conditions = append(conditions, "NOT is_up")
+	default:
+		return nil, fmt.Errorf("unknown status %q", query.Status)
+	}
+	if query.MinLatency > 0 {
+		conditions = append(conditions, "response_time_ms >= ?")
+		args = append(args, query.MinLatency.Milliseconds())
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	// A negative limit means no limit in SQLite
+	limit := query.Limit
+	if limit <= 0 {
+		limit = -1
+	}
+	args = append(args, limit)
+
+	rows, err := s.db.Query(`
+		SELECT id, service_id, service_name, service_url, is_up, status_code, response_time_ms, error_message, checked_at, state, attempts, degraded
 		FROM checks
-		WHERE service_name = ?
-		ORDER BY checked_at DESC
+		`+where+`
+		ORDER BY checked_at DESC, id DESC
 		LIMIT ?
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query history: %w", err)
+	}
+	defer rows.Close()
+
+	return scanCheckRecords(rows)
+}
+
+// GetIncidentChecks retrieves the checks that belong to an incident, oldest first
+func (s *SQLiteStorage) GetIncidentChecks(incidentID int64) ([]CheckRecord, error) {
+	query := `
+		SELECT id, service_id, service_name, service_url, is_up, status_code, response_time_ms, error_message, checked_at, state, attempts, degraded
+		FROM checks
+		WHERE incident_id = ?
+		ORDER BY checked_at, id
 	`
 
-	rows, err := s.db.Query(query, serviceName, limit)
+	rows, err := s.db.Query(query, incidentID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query history: %w", err)
+		return nil, fmt.Errorf("failed to query incident checks: %w", err)
 	}
 	defer rows.Close()
 
+	return scanCheckRecords(rows)
+}
+
+// scanCheckRecords reads check records from the rows of a checks query
+func scanCheckRecords(rows *sql.Rows) ([]CheckRecord, error) {
 	var records []CheckRecord
 	for rows.Next() {
 		var r CheckRecord
 		err := rows.Scan(
 			&r.ID,
+			&r.ServiceID,
 			&r.ServiceName,
 			&r.ServiceURL,
 			&r.IsUp,
@@ -113,6 +432,9 @@ func (s *SQLiteStorage) GetHistory(serviceName string, limit int) ([]CheckRecord
 			&r.ResponseTimeMs,
 			&r.ErrorMessage,
 			&r.CheckedAt,
+			&r.State,
+			&r.Attempts,
+			&r.Degraded,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan row: %w", err)
@@ -120,14 +442,316 @@ func (s *SQLiteStorage) GetHistory(serviceName string, limit int) ([]CheckRecord
 		records = append(records, r)
 	}
 
-	if err = rows.Err(); err != nil {
+	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("error iterating rows: %w", err)
 	}
 
 	return records, nil
 }
 
-// Cleanup removes old records based on retention policy
+// GetIncidents retrieves the most recent incidents, newest first. An empty
+// serviceID returns the incidents of all services.
+func (s *SQLiteStorage) GetIncidents(serviceID string, limit int) ([]Incident, error) {
+	query := `
+		SELECT id, service_id, service_name, started_at, ended_at, duration_seconds, first_error, checks
+		FROM incidents
+		WHERE ? = '' OR service_id = ?
+		ORDER BY started_at DESC, id DESC
+		LIMIT ?
+	`
+
+	rows, err := s.db.Query(query, serviceID, serviceID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query incidents: %w", err)
+	}
+	defer rows.Close()
+
+	var incidents []Incident
+	for rows.Next() {
+		var (
+			incident Incident
+			endedAt  sql.NullTime
+			duration sql.NullInt64
+		)
+		err := rows.Scan(
+			&incident.ID,
+			&incident.ServiceID,
+			&incident.ServiceName,
+			&incident.StartedAt,
+			&endedAt,
+			&duration,
+			&incident.FirstError,
+			&incident.Checks,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan incident: %w", err)
+		}
+		if endedAt.Valid {
+			incident.EndedAt = endedAt.Time
+		}
+		incident.Duration = time.Duration(duration.Int64) * time.Second
+		incidents = append(incidents, incident)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating incidents: %w", err)
+	}
+
+	return incidents, nil
+}
+
+// sqliteTime formats a time like CURRENT_TIMESTAMP, so it can be compared
+// with checked_at and started_at
+func sqliteTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02 15:04:05")
+}
+
+// GetReport aggregates the checks and incidents of every service between
+// from and to, ordered by service ID
+func (s *SQLiteStorage) GetReport(from, to time.Time) ([]ServiceReport, error) {
+	reports := make(map[string]*ServiceReport)
+	report := func(id string) *ServiceReport {
+		if reports[id] == nil {
+			reports[id] = &ServiceReport{ServiceID: id}
+		}
+		return reports[id]
+	}
+
+	// The service name is taken from the latest check: SQLite returns bare
+	// columns from the row that matched MAX()
+	rows, err := s.db.Query(`
+		SELECT service_id, service_name, MAX(id), COUNT(*),
+			SUM(CASE WHEN state = 'DOWN' OR (state = '' AND NOT is_up) THEN 0 ELSE 1 END)
+		FROM checks
+		WHERE checked_at >= ? AND checked_at < ?
+		GROUP BY service_id
+	`, sqliteTime(from), sqliteTime(to))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query check totals: %w", err)
+	}
+	for rows.Next() {
+		var (
+			id, name string
+			lastID   int64
+			checks   int
+			upChecks int
+		)
+		if err := rows.Scan(&id, &name, &lastID, &checks, &upChecks); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan check totals: %w", err)
+		}
+		r := report(id)
+		r.ServiceName = name
+		r.Checks = checks
+		r.UpChecks = upChecks
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating check totals: %w", err)
+	}
+
+	rows, err = s.db.Query(`
+		SELECT service_id, service_name, COUNT(*), COUNT(ended_at), COALESCE(SUM(duration_seconds), 0)
+		FROM incidents
+		WHERE started_at >= ? AND started_at < ?
+		GROUP BY service_id
+	`, sqliteTime(from), sqliteTime(to))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query incident totals: %w", err)
+	}
+	for rows.Next() {
+		var (
+			id, name   string
+			incidents  int
+			resolved   int
+			repairTime int64
+		)
+		if err := rows.Scan(&id, &name, &incidents, &resolved, &repairTime); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan incident totals: %w", err)
+		}
+		r := report(id)
+		if r.ServiceName == "" {
+			r.ServiceName = name
+		}
+		r.Incidents = incidents
+		r.Resolved = resolved
+		r.RepairTime = time.Duration(repairTime) * time.Second
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating incident totals: %w", err)
+	}
+
+	// SQLite has no percentile function, so the sorted response times are
+	// read back and ranked here
+	rows, err = s.db.Query(`
+		SELECT service_id, response_time_ms
+		FROM checks
+		WHERE checked_at >= ? AND checked_at < ? AND is_up
+		ORDER BY service_id, response_time_ms
+	`, sqliteTime(from), sqliteTime(to))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query response times: %w", err)
+	}
+	defer rows.Close()
+
+	var (
+		current string
+		times   []int64
+	)
+	flush := func() {
+		if len(times) > 0 {
+			r := report(current)
+			r.Samples = len(times)
+			r.P50 = percentile(times, 50)
+			r.P95 = percentile(times, 95)
+			r.P99 = percentile(times, 99)
+		}
+		times = times[:0]
+	}
+	for rows.Next() {
+		var (
+			id string
+			ms int64
+		)
+		if err := rows.Scan(&id, &ms); err != nil {
+			return nil, fmt.Errorf("failed to scan response time: %w", err)
+		}
+		if id != current {
+			flush()
+			current = id
+		}
+		times = append(times, ms)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating response times: %w", err)
+	}
+	flush()
+
+	result := make([]ServiceReport, 0, len(reports))
+	for _, r := range reports {
+		result = append(result, *r)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ServiceID < result[j].ServiceID
+	})
+	return result, nil
+}
+
+// percentile returns the nearest-rank percentile p of sorted response times
+// in milliseconds
+func percentile(sorted []int64, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return time.Duration(sorted[rank-1]) * time.Millisecond
+}
+
+// SaveState persists the tracked state of a service, replacing any previous state
+func (s *SQLiteStorage) SaveState(state ServiceState) error {
+	var downSince sql.NullTime
+	if !state.DownSince.IsZero() {
+		downSince = sql.NullTime{Time: state.DownSince, Valid: true}
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to save state: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		INSERT INTO service_state (service_id, state, down_since, escalation_step, updated_at)
+		VALUES (?, ?, ?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT(service_id) DO UPDATE SET
+			state = excluded.state,
+			down_since = excluded.down_since,
+			escalation_step = excluded.escalation_step,
+			updated_at = excluded.updated_at
+	`, state.ServiceID, state.State, downSince, state.EscalationStep)
+	if err != nil {
+		return fmt.Errorf("failed to save state: %w", err)
+	}
+
+	for channel, notifiedAt := range state.LastNotification {
+		_, err = tx.Exec(`
+			INSERT INTO notification_state (service_id, channel, notified_at, reminders)
+			VALUES (?, ?, ?, ?)
+			ON CONFLICT(service_id, channel) DO UPDATE SET
+				notified_at = excluded.notified_at,
+				reminders = excluded.reminders
+		`, state.ServiceID, channel, notifiedAt, state.Reminders[channel])
+		if err != nil {
+			return fmt.Errorf("failed to save notification time: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to save state: %w", err)
+	}
+	return nil
+}
+
+// LoadStates retrieves the persisted state of every service
+func (s *SQLiteStorage) LoadStates() ([]ServiceState, error) {
+	rows, err := s.db.Query(`SELECT service_id, state, down_since, escalation_step FROM service_state ORDER BY service_id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query states: %w", err)
+	}
+	defer rows.Close()
+
+	var states []ServiceState
+	index := make(map[string]int)
+	for rows.Next() {
+		var (
+			state     ServiceState
+			downSince sql.NullTime
+		)
+		if err := rows.Scan(&state.ServiceID, &state.State, &downSince, &state.EscalationStep); err != nil {
+			return nil, fmt.Errorf("failed to scan state: %w", err)
+		}
+		if downSince.Valid {
+			state.DownSince = downSince.Time
+		}
+		state.LastNotification = make(map[string]time.Time)
+		state.Reminders = make(map[string]int)
+		index[state.ServiceID] = len(states)
+		states = append(states, state)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating states: %w", err)
+	}
+
+	notifications, err := s.db.Query(`SELECT service_id, channel, notified_at, reminders FROM notification_state`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query notification times: %w", err)
+	}
+	defer notifications.Close()
+
+	for notifications.Next() {
+		var (
+			id, channel string
+			notifiedAt  time.Time
+			reminders   int
+		)
+		if err := notifications.Scan(&id, &channel, &notifiedAt, &reminders); err != nil {
+			return nil, fmt.Errorf("failed to scan notification time: %w", err)
+		}
+		if i, ok := index[id]; ok {
+			states[i].LastNotification[channel] = notifiedAt
+			states[i].Reminders[channel] = reminders
+		}
+	}
+	if err := notifications.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating notification times: %w", err)
+	}
+
+	return states, nil
+}
+
+// Cleanup removes old records and closed incidents based on retention policy
 func (s *SQLiteStorage) Cleanup(retentionDays int) error {
 	query := `DELETE FROM checks WHERE checked_at < datetime('now', '-' || ? || ' days')`
 
@@ -141,6 +765,17 @@ func (s *SQLiteStorage) Cleanup(retentionDays int) error {
 		fmt.Printf("Cleaned up %d old records (older than %d days)\n", rowsAffected, retentionDays)
 	}
 
+	// Open incidents are kept however long ago they started
+	result, err = s.db.Exec(`DELETE FROM incidents WHERE ended_at < datetime('now', '-' || ? || ' days')`, retentionDays)
+	if err != nil {
+		return fmt.Errorf("failed to cleanup old incidents: %w", err)
+	}
+
+	rowsAffected, _ = result.RowsAffected()
+	if rowsAffected > 0 {
+		fmt.Printf("Cleaned up %d old incidents (ended more than %d days ago)\n", rowsAffected, retentionDays)
+	}
+
 	return nil
 }
 
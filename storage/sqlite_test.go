@@ -1,8 +1,12 @@
 package storage
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 
@@ -13,6 +17,7 @@ const (
 	testDBPath           = ":memory:"
 	testServiceName      = "Test Service"
 	testServiceURL       = "https://example.com"
+	testServiceID        = "test-service"
 	errMsgCreateStorage  = "Failed to create storage: %v"
 	errMsgSaveCheck      = "Failed to save check: %v"
 	errMsgGetHistory     = "Failed to get history: %v"
@@ -75,7 +80,7 @@ func TestGetHistory(t *testing.T) {
 	}
 
 	// Retrieve history
-	records, err := store.GetHistory(testServiceName, 10)
+	records, err := store.GetHistory(testServiceID, 10)
 	if err != nil {
 		t.Fatalf(errMsgGetHistory, err)
 	}
@@ -114,7 +119,7 @@ func TestGetHistoryLimit(t *testing.T) {
 	}
 
 	// Retrieve only 5
-	records, err := store.GetHistory(testServiceName, 5)
+	records, err := store.GetHistory(testServiceID, 5)
 	if err != nil {
 		t.Fatalf(errMsgGetHistory, err)
 	}
@@ -131,7 +136,7 @@ func TestGetHistoryNonExistent(t *testing.T) {
 	}
 	defer store.Close()
 
-	records, err := store.GetHistory("NonExistent Service", 10)
+	records, err := store.GetHistory("nonexistent-service", 10)
 	if err != nil {
 		t.Fatalf(errMsgGetHistory, err)
 	}
@@ -154,9 +159,9 @@ func TestCleanup(t *testing.T) {
 	// Insert old record (simulate by direct SQL)
 	oldDate := time.Now().AddDate(0, 0, -35) // 35 days ago
 	_, err = store.db.Exec(`
-		INSERT INTO checks (service_name, service_url, is_up, status_code, response_time_ms, checked_at)
-		VALUES (?, ?, ?, ?, ?, ?)
-	`, "Old Service", "https://old.com", true, 200, 100, oldDate)
+		INSERT INTO checks (service_id, service_name, service_url, is_up, status_code, response_time_ms, checked_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, "old-service", "Old Service", "https://old.com", true, 200, 100, oldDate)
 	if err != nil {
 		t.Fatalf("Failed to insert old record: %v", err)
 	}
@@ -180,13 +185,13 @@ func TestCleanup(t *testing.T) {
 	}
 
 	// Verify old record is deleted
-	oldRecords, _ := store.GetHistory("Old Service", 10)
+	oldRecords, _ := store.GetHistory("old-service", 10)
 	if len(oldRecords) != 0 {
 		t.Errorf("Expected old records to be deleted, got %d", len(oldRecords))
 	}
 
 	// Verify recent record still exists
-	recentRecords, _ := store.GetHistory("Recent Service", 10)
+	recentRecords, _ := store.GetHistory("recent-service", 10)
 	if len(recentRecords) != 1 {
 		t.Errorf("Expected recent record to remain, got %d", len(recentRecords))
 	}
@@ -213,7 +218,7 @@ func TestSaveCheckWithError(t *testing.T) {
 		t.Errorf("Failed to save check with error: %v", err)
 	}
 
-	records, _ := store.GetHistory("Failed Service", 1)
+	records, _ := store.GetHistory("failed-service", 1)
 	if len(records) == 0 {
 		t.Fatal("Expected to retrieve saved record")
 	}
@@ -222,3 +227,550 @@ func TestSaveCheckWithError(t *testing.T) {
 		t.Errorf("Expected error message 'connection timeout', got '%s'", records[0].ErrorMessage)
 	}
 }
+
+func TestSaveCheckState(t *testing.T) {
+	store, err := NewSQLiteStorage(testDBPath)
+	if err != nil {
+		t.Fatalf(errMsgCreateStorage, err)
+	}
+	defer store.Close()
+
+	check := checker.ServiceStatus{
+		Name:  testServiceName,
+		URL:   testServiceURL,
+		IsUp:  false,
+		State: checker.StateUp,
+	}
+	if err := store.SaveCheck(check); err != nil {
+		t.Fatalf(errMsgSaveCheck, err)
+	}
+
+	records, err := store.GetHistory(testServiceID, 1)
+	if err != nil {
+		t.Fatalf(errMsgGetHistory, err)
+	}
+	if len(records) != 1 || records[0].State != checker.StateUp {
+		t.Errorf("Expected stored state UP, got %+v", records)
+	}
+}
+
+func TestSaveCheckAttempts(t *testing.T) {
+	store, err := NewSQLiteStorage(testDBPath)
+	if err != nil {
+		t.Fatalf(errMsgCreateStorage, err)
+	}
+	defer store.Close()
+
+	checks := []checker.ServiceStatus{
+		{Name: testServiceName, URL: testServiceURL, IsUp: true},
+		{Name: testServiceName, URL: testServiceURL, IsUp: true, Attempts: make([]checker.Attempt, 3)},
+	}
+	for _, check := range checks {
+		if err := store.SaveCheck(check); err != nil {
+			t.Fatalf(errMsgSaveCheck, err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	records, err := store.GetHistory(testServiceID, 2)
+	if err != nil {
+		t.Fatalf(errMsgGetHistory, err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 records, got %d", len(records))
+	}
+	// Most recent first
+	if records[0].Attempts != 3 {
+		t.Errorf("Expected 3 attempts, got %d", records[0].Attempts)
+	}
+	if records[1].Attempts != 1 {
+		t.Errorf("Expected a single attempt without retries, got %d", records[1].Attempts)
+	}
+}
+
+func TestSaveCheckDegraded(t *testing.T) {
+	store, err := NewSQLiteStorage(testDBPath)
+	if err != nil {
+		t.Fatalf(errMsgCreateStorage, err)
+	}
+	defer store.Close()
+
+	check := checker.ServiceStatus{
+		Name:     testServiceName,
+		URL:      testServiceURL,
+		IsUp:     true,
+		Degraded: true,
+		State:    checker.StateDegraded,
+	}
+	if err := store.SaveCheck(check); err != nil {
+		t.Fatalf(errMsgSaveCheck, err)
+	}
+
+	records, err := store.GetHistory(testServiceID, 1)
+	if err != nil {
+		t.Fatalf(errMsgGetHistory, err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("Expected 1 record, got %d", len(records))
+	}
+	if records[0].Result() != checker.StateDegraded || records[0].State != checker.StateDegraded {
+		t.Errorf("Expected stored DEGRADED result and state, got %+v", records[0])
+	}
+}
+
+func TestGetHistoryByServiceID(t *testing.T) {
+	store, err := NewSQLiteStorage(testDBPath)
+	if err != nil {
+		t.Fatalf(errMsgCreateStorage, err)
+	}
+	defer store.Close()
+
+	// A renamed service keeps its ID, a different service with the same URL does not share it
+	checks := []checker.ServiceStatus{
+		{ID: "api", Name: "API", URL: testServiceURL, IsUp: true},
+		{ID: "api", Name: "Public API", URL: testServiceURL, IsUp: true},
+		{ID: "api-admin", Name: "API", URL: testServiceURL, IsUp: false},
+	}
+	for _, check := range checks {
+		if err := store.SaveCheck(check); err != nil {
+			t.Fatalf(errMsgSaveCheck, err)
+		}
+	}
+
+	records, err := store.GetHistory("api", 10)
+	if err != nil {
+		t.Fatalf(errMsgGetHistory, err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 records for id 'api', got %d", len(records))
+	}
+	for _, r := range records {
+		if r.ServiceID != "api" || !r.IsUp {
+			t.Errorf("Expected only UP records of id 'api', got %+v", r)
+		}
+	}
+}
+
+func TestMigrateLegacySchema(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "legacy.db")
+
+	// Create a database with the original checks table
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatalf("Failed to open legacy database: %v", err)
+	}
+	_, err = db.Exec(`
+		CREATE TABLE checks (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			service_name TEXT NOT NULL,
+			service_url TEXT NOT NULL,
+			is_up BOOLEAN NOT NULL,
+			status_code INTEGER,
+			response_time_ms INTEGER,
+			error_message TEXT,
+			checked_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		);
+		INSERT INTO checks (service_name, service_url, is_up, status_code, response_time_ms, error_message)
+		VALUES ('Legacy', 'https://legacy.example.com', 1, 200, 50, '');
+	`)
+	db.Close()
+	if err != nil {
+		t.Fatalf("Failed to create legacy schema: %v", err)
+	}
+
+	store, err := NewSQLiteStorage(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to open legacy database with migrations: %v", err)
+	}
+	defer store.Close()
+
+	// Legacy rows are found by the ID derived from their name
+	records, err := store.GetHistory("legacy", 10)
+	if err != nil {
+		t.Fatalf(errMsgGetHistory, err)
+	}
+	if len(records) != 1 || records[0].State != "" || records[0].ServiceID != "legacy" {
+		t.Errorf("Expected legacy record with empty state and derived ID, got %+v", records)
+	}
+
+	if err := store.SaveCheck(checker.ServiceStatus{Name: "Legacy", URL: "https://legacy.example.com", IsUp: true, State: checker.StateUp}); err != nil {
+		t.Fatalf(errMsgSaveCheck, err)
+	}
+}
+
+func TestSaveAndLoadStates(t *testing.T) {
+	store, err := NewSQLiteStorage(filepath.Join(t.TempDir(), "state.db"))
+	if err != nil {
+		t.Fatalf(errMsgCreateStorage, err)
+	}
+	defer store.Close()
+
+	downSince := time.Date(2025, 10, 11, 22, 30, 0, 0, time.UTC)
+	notifiedAt := downSince.Add(time.Minute)
+
+	states := []ServiceState{
+		{ServiceID: "a", State: checker.StateUp, LastNotification: map[string]time.Time{}},
+		{ServiceID: "b", State: checker.StateDown, DownSince: downSince, EscalationStep: 2, LastNotification: map[string]time.Time{"telegram": notifiedAt}, Reminders: map[string]int{"telegram": 2}},
+	}
+	for _, state := range states {
+		if err := store.SaveState(state); err != nil {
+			t.Fatalf("Failed to save state: %v", err)
+		}
+	}
+
+	// Saving again replaces the previous state
+	states[0].State = checker.StateDegraded
+	if err := store.SaveState(states[0]); err != nil {
+		t.Fatalf("Failed to update state: %v", err)
+	}
+
+	loaded, err := store.LoadStates()
+	if err != nil {
+		t.Fatalf("Failed to load states: %v", err)
+	}
+	if len(loaded) != 2 {
+		t.Fatalf("Expected 2 states, got %d", len(loaded))
+	}
+	if loaded[0].State != checker.StateDegraded || !loaded[0].DownSince.IsZero() {
+		t.Errorf("Expected updated DEGRADED state without down-since, got %+v", loaded[0])
+	}
+	if loaded[1].State != checker.StateDown || !loaded[1].DownSince.Equal(downSince) {
+		t.Errorf("Expected DOWN state since %v, got %+v", downSince, loaded[1])
+	}
+	if !loaded[1].LastNotification["telegram"].Equal(notifiedAt) {
+		t.Errorf("Expected telegram notified at %v, got %v", notifiedAt, loaded[1].LastNotification)
+	}
+	if loaded[1].EscalationStep != 2 {
+		t.Errorf("Expected escalation step 2, got %d", loaded[1].EscalationStep)
+	}
+	if loaded[1].Reminders["telegram"] != 2 {
+		t.Errorf("Expected 2 telegram reminders, got %v", loaded[1].Reminders)
+	}
+}
+
+func TestIncidentLifecycle(t *testing.T) {
+	store, err := NewSQLiteStorage(filepath.Join(t.TempDir(), "incidents.db"))
+	if err != nil {
+		t.Fatalf(errMsgCreateStorage, err)
+	}
+	defer store.Close()
+
+	up := checker.ServiceStatus{ID: "api", Name: "API", URL: testServiceURL, IsUp: true}
+	down := func(msg string) checker.ServiceStatus {
+		return checker.ServiceStatus{ID: "api", Name: "API", URL: testServiceURL, IsUp: false, Error: errors.New(msg)}
+	}
+	// A failure below the failure threshold keeps the confirmed state UP
+	pending := down("blip")
+	pending.State = checker.StateUp
+
+	checks := []checker.ServiceStatus{
+		up, pending, up,
+		down("connection refused"), down("timeout"), up,
+		down("502 bad gateway"),
+		{ID: "web", Name: "Web", URL: testServiceURL, IsUp: false},
+	}
+	for _, check := range checks {
+		if err := store.SaveCheck(check); err != nil {
+			t.Fatalf(errMsgSaveCheck, err)
+		}
+	}
+
+	incidents, err := store.GetIncidents("api", 10)
+	if err != nil {
+		t.Fatalf("Failed to get incidents: %v", err)
+	}
+	if len(incidents) != 2 {
+		t.Fatalf("Expected 2 incidents, got %d", len(incidents))
+	}
+
+	current, closed := incidents[0], incidents[1]
+	if !current.Open() || current.Checks != 1 || current.FirstError != "502 bad gateway" {
+		t.Errorf("Expected an open incident with one check, got %+v", current)
+	}
+	if closed.Open() || closed.Checks != 2 || closed.FirstError != "connection refused" {
+		t.Errorf("Expected a closed incident with two checks, got %+v", closed)
+	}
+	if closed.EndedAt.Before(closed.StartedAt) || closed.Duration < 0 {
+		t.Errorf("Expected the incident to end after it started, got %+v", closed)
+	}
+
+	records, err := store.GetIncidentChecks(closed.ID)
+	if err != nil {
+		t.Fatalf("Failed to get incident checks: %v", err)
+	}
+	if len(records) != 2 || records[0].ErrorMessage != "connection refused" || records[1].ErrorMessage != "timeout" {
+		t.Errorf("Expected the two DOWN checks of the incident, got %+v", records)
+	}
+
+	all, err := store.GetIncidents("", 10)
+	if err != nil {
+		t.Fatalf("Failed to get incidents: %v", err)
+	}
+	if len(all) != 3 || all[0].ServiceID != "web" {
+		t.Errorf("Expected 3 incidents across services, newest first, got %+v", all)
+	}
+}
+
+func TestCleanupIncidents(t *testing.T) {
+	store, err := NewSQLiteStorage(filepath.Join(t.TempDir(), "cleanup.db"))
+	if err != nil {
+		t.Fatalf(errMsgCreateStorage, err)
+	}
+	defer store.Close()
+
+	down := checker.ServiceStatus{ID: "api", Name: "API", URL: testServiceURL, IsUp: false, Error: errors.New("timeout")}
+	up := checker.ServiceStatus{ID: "api", Name: "API", URL: testServiceURL, IsUp: true}
+	for _, check := range []checker.ServiceStatus{down, up, down, up, down} {
+		if err := store.SaveCheck(check); err != nil {
+			t.Fatalf(errMsgSaveCheck, err)
+		}
+	}
+
+	// Age the first closed incident and its checks past retention, and make
+	// the open one start long ago
+	incidents, err := store.GetIncidents("api", 10)
+	if err != nil || len(incidents) != 3 {
+		t.Fatalf("Expected 3 incidents, got %d: %v", len(incidents), err)
+	}
+	old, recent, open := incidents[2], incidents[1], incidents[0]
+	if _, err := store.db.Exec(`UPDATE incidents SET started_at = datetime('now', '-40 days'), ended_at = datetime('now', '-35 days') WHERE id = ?`, old.ID); err != nil {
+		t.Fatalf("Failed to age incident: %v", err)
+	}
+	if _, err := store.db.Exec(`UPDATE incidents SET started_at = datetime('now', '-60 days') WHERE id = ?`, open.ID); err != nil {
+		t.Fatalf("Failed to age incident: %v", err)
+	}
+	if _, err := store.db.Exec(`UPDATE checks SET checked_at = datetime('now', '-35 days') WHERE incident_id = ?`, old.ID); err != nil {
+		t.Fatalf("Failed to age checks: %v", err)
+	}
+
+	if err := store.Cleanup(30); err != nil {
+		t.Fatalf("Cleanup failed: %v", err)
+	}
+
+	incidents, err = store.GetIncidents("api", 10)
+	if err != nil {
+		t.Fatalf("Failed to get incidents: %v", err)
+	}
+	if len(incidents) != 2 || incidents[0].ID != recent.ID || incidents[1].ID != open.ID {
+		t.Errorf("Expected the open and the recently closed incident to remain, got %+v", incidents)
+	}
+	if records, _ := store.GetIncidentChecks(recent.ID); len(records) != 1 {
+		t.Errorf("Expected the check of the recent incident to remain, got %d", len(records))
+	}
+}
+
+func TestSaveCheckAndStateConcurrent(t *testing.T) {
+	store, err := NewSQLiteStorage(filepath.Join(t.TempDir(), "concurrent.db"))
+	if err != nil {
+		t.Fatalf(errMsgCreateStorage, err)
+	}
+	defer store.Close()
+
+	// Every worker saves the checks and state of its own service, as run
+	// does: outages of four DOWN checks, each followed by four UP checks
+	const workers, saves = 5, 40
+	notified := time.Date(2025, 10, 12, 9, 0, 0, 0, time.UTC)
+	errs := make(chan error, workers*saves)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			for i := 0; i < saves; i++ {
+				check := checker.ServiceStatus{ID: id, Name: id, URL: testServiceURL, IsUp: i%8 >= 4}
+				if !check.IsUp {
+					check.Error = errors.New("connection refused")
+				}
+				if err := store.SaveCheck(check); err != nil {
+					errs <- err
+				}
+
+				state := ServiceState{
+					ServiceID:        id,
+					State:            checker.StateUp,
+					LastNotification: map[string]time.Time{"telegram": notified.Add(time.Duration(i) * time.Minute)},
+					Reminders:        map[string]int{"telegram": i},
+				}
+				if !check.IsUp {
+					state.State, state.DownSince = checker.StateDown, notified
+				}
+				if err := store.SaveState(state); err != nil {
+					errs <- err
+				}
+			}
+		}(fmt.Sprintf("service-%d", w))
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf(errMsgSaveCheck, err)
+	}
+
+	records, err := store.QueryHistory(HistoryQuery{})
+	if err != nil {
+		t.Fatalf(errMsgGetHistory, err)
+	}
+	if len(records) != workers*saves {
+		t.Errorf("Expected %d checks, got %d", workers*saves, len(records))
+	}
+
+	for w := 0; w < workers; w++ {
+		id := fmt.Sprintf("service-%d", w)
+		incidents, err := store.GetIncidents(id, 100)
+		if err != nil {
+			t.Fatalf("Failed to get incidents: %v", err)
+		}
+		if len(incidents) != saves/8 {
+			t.Errorf("%s: expected %d incidents, got %d", id, saves/8, len(incidents))
+		}
+		for _, incident := range incidents {
+			if incident.Open() || incident.Checks != 4 {
+				t.Errorf("%s: expected a closed incident with 4 checks, got %+v", id, incident)
+			}
+		}
+	}
+
+	// Every service's persisted state is the one saved after its last check
+	states, err := store.LoadStates()
+	if err != nil {
+		t.Fatalf("Failed to load states: %v", err)
+	}
+	if len(states) != workers {
+		t.Fatalf("Expected %d states, got %d", workers, len(states))
+	}
+	last := notified.Add((saves - 1) * time.Minute)
+	for _, state := range states {
+		if state.State != checker.StateUp || !state.DownSince.IsZero() ||
+			!state.LastNotification["telegram"].Equal(last) || state.Reminders["telegram"] != saves-1 {
+			t.Errorf("Expected the state of the last check, got %+v", state)
+		}
+	}
+}
+
+func TestGetReport(t *testing.T) {
+	store, err := NewSQLiteStorage(filepath.Join(t.TempDir(), "report.db"))
+	if err != nil {
+		t.Fatalf(errMsgCreateStorage, err)
+	}
+	defer store.Close()
+
+	var checks []checker.ServiceStatus
+	for ms := 100; ms <= 1000; ms += 100 {
+		checks = append(checks, checker.ServiceStatus{ID: "api", Name: "API", URL: testServiceURL, IsUp: true, ResponseTime: time.Duration(ms) * time.Millisecond})
+	}
+	checks = append(checks,
+		checker.ServiceStatus{ID: "api", Name: "API", URL: testServiceURL, IsUp: false, ResponseTime: 5 * time.Second},
+		checker.ServiceStatus{ID: "api", Name: "API", URL: testServiceURL, IsUp: true, ResponseTime: 100 * time.Millisecond},
+		checker.ServiceStatus{ID: "web", Name: "Web", URL: testServiceURL, IsUp: true, ResponseTime: 50 * time.Millisecond},
+	)
+	for _, check := range checks {
+		if err := store.SaveCheck(check); err != nil {
+			t.Fatalf(errMsgSaveCheck, err)
+		}
+	}
+
+	// Give the resolved incident a known duration, and add an older check
+	// and incident outside of the window
+	if _, err := store.db.Exec(`UPDATE incidents SET duration_seconds = 600`); err != nil {
+		t.Fatalf("Failed to update incident: %v", err)
+	}
+	if _, err := store.db.Exec(`INSERT INTO checks (service_id, service_name, service_url, is_up, checked_at) VALUES ('web', 'Web', '', 0, datetime('now', '-2 days'))`); err != nil {
+		t.Fatalf("Failed to insert old check: %v", err)
+	}
+	if _, err := store.db.Exec(`INSERT INTO incidents (service_id, service_name, started_at) VALUES ('web', 'Web', datetime('now', '-2 days'))`); err != nil {
+		t.Fatalf("Failed to insert old incident: %v", err)
+	}
+
+	now := time.Now()
+	reports, err := store.GetReport(now.Add(-time.Hour), now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Failed to get report: %v", err)
+	}
+	if len(reports) != 2 {
+		t.Fatalf("Expected reports for 2 services, got %d", len(reports))
+	}
+
+	api := reports[0]
+	if api.ServiceID != "api" || api.ServiceName != "API" {
+		t.Fatalf("Expected the api report first, got %+v", api)
+	}
+	if api.Checks != 12 || api.UpChecks != 11 {
+		t.Errorf("Expected 11 of 12 checks up, got %d of %d", api.UpChecks, api.Checks)
+	}
+	if api.Incidents != 1 || api.Resolved != 1 || api.MTTR() != 10*time.Minute {
+		t.Errorf("Expected one resolved incident with MTTR 10m, got %+v", api)
+	}
+	if api.Samples != 11 {
+		t.Errorf("Expected 11 response time samples, got %d", api.Samples)
+	}
+	if api.P50 != 500*time.Millisecond || api.P95 != time.Second || api.P99 != time.Second {
+		t.Errorf("Expected p50/p95/p99 of 500ms/1s/1s, got %v/%v/%v", api.P50, api.P95, api.P99)
+	}
+	if got := api.MTBF(12 * time.Hour); got != 11*time.Hour {
+		t.Errorf("Expected MTBF 11h over a 12h window, got %v", got)
+	}
+
+	web := reports[1]
+	if web.Checks != 1 || web.Samples != 1 || web.Uptime() != 100 || web.Incidents != 0 || web.MTBF(time.Hour) != 0 {
+		t.Errorf("Expected the old check and incident of web to be excluded, got %+v", web)
+	}
+}
+
+func TestQueryHistory(t *testing.T) {
+	store, err := NewSQLiteStorage(filepath.Join(t.TempDir(), "history.db"))
+	if err != nil {
+		t.Fatalf(errMsgCreateStorage, err)
+	}
+	defer store.Close()
+
+	checks := []checker.ServiceStatus{
+		{ID: "api", Name: "API", URL: testServiceURL, IsUp: true, ResponseTime: 100 * time.Millisecond},
+		{ID: "api", Name: "API", URL: testServiceURL, IsUp: true, Degraded: true, ResponseTime: 3 * time.Second},
+		{ID: "api", Name: "API", URL: testServiceURL, IsUp: false, ResponseTime: 5 * time.Second},
+		{ID: "web", Name: "Web", URL: testServiceURL, IsUp: true, ResponseTime: 800 * time.Millisecond},
+		{ID: "db", Name: "DB", URL: testServiceURL, IsUp: false},
+	}
+	for _, check := range checks {
+		if err := store.SaveCheck(check); err != nil {
+			t.Fatalf(errMsgSaveCheck, err)
+		}
+	}
+	if _, err := store.db.Exec(`INSERT INTO checks (service_id, service_name, service_url, is_up, status_code, response_time_ms, error_message, checked_at) VALUES ('api', 'API', '', 1, 200, 10, '', datetime('now', '-2 days'))`); err != nil {
+		t.Fatalf("Failed to insert old check: %v", err)
+	}
+
+	now := time.Now()
+	tests := []struct {
+		name  string
+		query HistoryQuery
+		want  int
+	}{
+		{name: "everything", query: HistoryQuery{}, want: 6},
+		{name: "limit", query: HistoryQuery{Limit: 2}, want: 2},
+		{name: "one service", query: HistoryQuery{ServiceIDs: []string{"api"}}, want: 4},
+		{name: "several services", query: HistoryQuery{ServiceIDs: []string{"web", "db"}}, want: 2},
+		{name: "since", query: HistoryQuery{ServiceIDs: []string{"api"}, Since: now.Add(-time.Hour)}, want: 3},
+		{name: "until", query: HistoryQuery{Until: now.Add(-time.Hour)}, want: 1},
+		{name: "up", query: HistoryQuery{Status: "up", Since: now.Add(-time.Hour)}, want: 2},
+		{name: "degraded", query: HistoryQuery{Status: "degraded"}, want: 1},
+		{name: "down", query: HistoryQuery{Status: "DOWN"}, want: 2},
+		{name: "min latency", query: HistoryQuery{MinLatency: time.Second}, want: 2},
+		{name: "combined", query: HistoryQuery{ServiceIDs: []string{"api", "web"}, Status: "up", MinLatency: 500 * time.Millisecond}, want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records, err := store.QueryHistory(tt.query)
+			if err != nil {
+				t.Fatalf("Failed to query history: %v", err)
+			}
+			if len(records) != tt.want {
+				t.Errorf("Expected %d records, got %d", tt.want, len(records))
+			}
+		})
+	}
+
+	if _, err := store.QueryHistory(HistoryQuery{Status: "sideways"}); err == nil {
+		t.Error("Expected error for an unknown status")
+	}
+}
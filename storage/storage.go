@@ -11,19 +11,48 @@ type Storage interface {
 	// SaveCheck saves a service check result to storage
 	SaveCheck(check checker.ServiceStatus) error
 
-	// GetHistory retrieves check history for a service
-	GetHistory(serviceName string, limit int) ([]CheckRecord, error)
+	// GetHistory retrieves check history for a service by its ID
+	GetHistory(serviceID string, limit int) ([]CheckRecord, error)
 
-	// Cleanup removes old records based on retention policy
+	// QueryHistory retrieves the checks matching a query, newest first
+	QueryHistory(query HistoryQuery) ([]CheckRecord, error)
+
+	// SaveState persists the tracked state of a service, replacing any previous state
+	SaveState(state ServiceState) error
+
+	// LoadStates retrieves the persisted state of every service
+	LoadStates() ([]ServiceState, error)
+
+	// GetIncidents retrieves the most recent incidents, of all services if serviceID is empty
+	GetIncidents(serviceID string, limit int) ([]Incident, error)
+
+	// GetIncidentChecks retrieves the checks that belong to an incident
+	GetIncidentChecks(incidentID int64) ([]CheckRecord, error)
+
+	// GetReport aggregates the checks and incidents of every service between from and to
+	GetReport(from, to time.Time) ([]ServiceReport, error)
+
+	// Cleanup removes old records and closed incidents based on retention policy
 	Cleanup(retentionDays int) error
 
 	// Close closes the storage connection
 	Close() error
 }
 
+// HistoryQuery selects stored checks. Zero fields do not filter.
+type HistoryQuery struct {
+	ServiceIDs []string
+	Since      time.Time // inclusive
+	Until      time.Time // exclusive
+	Status     string    // result of the check: UP, DEGRADED or DOWN
+	MinLatency time.Duration
+	Limit      int
+}
+
 // CheckRecord represents a stored check result
 type CheckRecord struct {
 	ID             int64
+	ServiceID      string
 	ServiceName    string
 	ServiceURL     string
 	IsUp           bool
@@ -31,4 +60,99 @@ type CheckRecord struct {
 	ResponseTimeMs int64
 	ErrorMessage   string
 	CheckedAt      time.Time
+	State          string
+	Attempts       int
+	Degraded       bool
+}
+
+// Result returns the state reported by the check alone: UP, DEGRADED or DOWN
+func (r CheckRecord) Result() string {
+	switch {
+	case !r.IsUp:
+		return checker.StateDown
+	case r.Degraded:
+		return checker.StateDegraded
+	default:
+		return checker.StateUp
+	}
+}
+
+// Incident is an outage of a service, from the first check in the DOWN state
+// to the first check after it that is not
+type Incident struct {
+	ID          int64
+	ServiceID   string
+	ServiceName string
+	StartedAt   time.Time
+	EndedAt     time.Time     // zero while the incident is open
+	Duration    time.Duration // zero while the incident is open
+	FirstError  string
+	Checks      int // checks in the DOWN state during the incident
+}
+
+// Open reports whether the service is still down
+func (i Incident) Open() bool {
+	return i.EndedAt.IsZero()
+}
+
+// ServiceReport aggregates the checks and incidents of a service over a time window
+type ServiceReport struct {
+	ServiceID   string
+	ServiceName string
+	Checks      int
+	UpChecks    int           // checks not in the DOWN state
+	Incidents   int           // incidents started in the window
+	Resolved    int           // incidents started in the window that are closed
+	RepairTime  time.Duration // total duration of the resolved incidents
+
+	// Response time percentiles of the successful checks, zero without samples
+	Samples int // successful checks the percentiles are computed from
+	P50     time.Duration
+	P95     time.Duration
+	P99     time.Duration
+}
+
+// Uptime returns the percentage of checks that were not in the DOWN state
+func (r ServiceReport) Uptime() float64 {
+	if r.Checks == 0 {
+		return 0
+	}
+	return float64(r.UpChecks) / float64(r.Checks) * 100
+}
+
+// MTTR returns the mean time to recovery of the resolved incidents, or zero
+// if no incident was resolved
+func (r ServiceReport) MTTR() time.Duration {
+	if r.Resolved == 0 {
+		return 0
+	}
+	return r.RepairTime / time.Duration(r.Resolved)
+}
+
+// MTBF returns the mean time between failures: the time the service was up
+// during the window divided by the number of incidents, or zero without
+// incidents
+func (r ServiceReport) MTBF(window time.Duration) time.Duration {
+	if r.Incidents == 0 || r.Checks == 0 {
+		return 0
+	}
+	uptime := time.Duration(float64(window) * float64(r.UpChecks) / float64(r.Checks))
+	return uptime / time.Duration(r.Incidents)
+}
+
+// ServiceState is the monitor state of a service that survives restarts
+type ServiceState struct {
+	ServiceID string
+	State     string
+	DownSince time.Time // zero unless the service is DOWN
+
+	// EscalationStep is the number of escalation steps sent during the
+	// current outage
+	EscalationStep int
+
+	// LastNotification holds when each notification channel last alerted
+	// about the service, Reminders how many "still down" reminders it sent
+	// during the current outage
+	LastNotification map[string]time.Time
+	Reminders        map[string]int
 }